@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"trivia/api"
+)
+
+func apiServer(args []string) error {
+	flags := flag.NewFlagSet("api", flag.ExitOnError)
+	address := flags.String("addr", ":8081", "Address to listen on")
+	flags.Parse(args)
+
+	quizGame, _ := newQuiz(0)
+	configuration, err := quizGame.ReadConfigurationFromYAML(configFile)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", *address)
+	if err != nil {
+		return err
+	}
+	server := api.NewServer(quizGame, configuration)
+
+	fmt.Printf("Serving the API on http://%s/, see http://%s/openapi.json\n", listener.Addr(), listener.Addr())
+	return http.Serve(listener, server.Handler())
+}
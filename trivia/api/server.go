@@ -0,0 +1,532 @@
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"trivia/quiz"
+)
+
+//go:embed openapi.json
+var openAPI []byte
+
+type Deck struct {
+	Name      string `json:"name"`
+	File      string `json:"file"`
+	Questions int    `json:"questions"`
+}
+
+// A question of a deck together with its position in the deck
+type DeckQuestion struct {
+	Index int `json:"index"`
+	quiz.Question
+}
+
+type SessionQuestion struct {
+	Question string              `json:"question"`
+	Content  []quiz.ContentBlock `json:"content,omitempty"`
+	Options  map[string]string   `json:"options"`
+}
+
+type SessionState struct {
+	ID              string           `json:"id"`
+	Player          string           `json:"player"`
+	Number          int              `json:"number"`
+	NumberQuestions int              `json:"number_questions"`
+	CorrectAnswers  int              `json:"correct_answers"`
+	Finished        bool             `json:"finished"`
+	Question        *SessionQuestion `json:"question,omitempty"`
+}
+
+type AnswerResult struct {
+	Correct     bool         `json:"correct"`
+	Answer      string       `json:"answer"`
+	RightAnswer string       `json:"right_answer"`
+	Explanation string       `json:"explanation,omitempty"`
+	Source      string       `json:"source,omitempty"`
+	Session     SessionState `json:"session"`
+}
+
+type sessionRequest struct {
+	Player string `json:"player"`
+	Deck   string `json:"deck"`
+	Amount int    `json:"amount"`
+}
+
+type answerRequest struct {
+	Choice string `json:"choice"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Error carrying the HTTP status to answer with
+type apiError struct {
+	status  int
+	message string
+}
+
+func (err apiError) Error() string {
+	return err.message
+}
+
+func newError(status int, format string, args ...interface{}) error {
+	return apiError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+// Sessions nobody has used for this long are dropped
+const sessionIdleTimeout time.Duration = time.Hour
+
+// A session together with the time it was last used
+type serverSession struct {
+	*quiz.Session
+	used time.Time
+}
+
+// Serves the question decks of the configuration, single-player sessions,
+// the game history and leaderboards as a JSON API. The decks are the local
+// question files listed in the configuration, named by their file name.
+type Server struct {
+	quiz          quiz.QuizInterface
+	configuration quiz.Configuration
+	history       *quiz.History
+	profiles      *quiz.Profiles
+
+	mutex    sync.Mutex
+	sessions map[string]*serverSession
+}
+
+func NewServer(quizGame quiz.QuizInterface, configuration quiz.Configuration) *Server {
+	dataDir := quiz.DataDir(configuration)
+	return &Server{
+		quiz:          quizGame,
+		configuration: configuration,
+		history:       quiz.NewHistory(dataDir),
+		profiles:      quiz.NewProfiles(dataDir),
+		sessions:      map[string]*serverSession{},
+	}
+}
+
+func (server *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/openapi.json", server.handleOpenAPI)
+	mux.HandleFunc("/decks", server.handle(server.decks))
+	mux.HandleFunc("/decks/", server.handle(server.deckQuestions))
+	mux.HandleFunc("/sessions", server.handle(server.createSession))
+	mux.HandleFunc("/sessions/", server.handle(server.session))
+	mux.HandleFunc("/history", server.handle(server.gameHistory))
+	mux.HandleFunc("/history/", server.handle(server.game))
+	mux.HandleFunc("/leaderboard", server.handle(server.leaderboard))
+
+	return mux
+}
+
+// Turns a function returning a status and a value into a handler writing
+// the value, or the error, as JSON
+func (server *Server) handle(endpoint func(request *http.Request) (int, interface{}, error)) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		status, value, err := endpoint(request)
+		if err != nil {
+			status = http.StatusInternalServerError
+			if apiErr, ok := err.(apiError); ok {
+				status = apiErr.status
+			}
+			writeJSON(writer, status, errorResponse{Error: err.Error()})
+			return
+		}
+		if status == http.StatusNoContent {
+			writer.WriteHeader(status)
+			return
+		}
+		writeJSON(writer, status, value)
+	}
+}
+
+func (server *Server) handleOpenAPI(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write(openAPI)
+}
+
+// GET /decks
+func (server *Server) decks(request *http.Request) (int, interface{}, error) {
+	if request.Method != http.MethodGet {
+		return 0, nil, methodNotAllowed(request)
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	decks := []Deck{}
+	for _, file := range server.configuration.Decks {
+		questions, err := quiz.ReadDeck(file)
+		if err != nil {
+			return 0, nil, err
+		}
+		decks = append(decks, Deck{Name: deckName(file), File: file, Questions: len(questions)})
+	}
+
+	return http.StatusOK, decks, nil
+}
+
+// GET and POST /decks/{deck}/questions, GET, PUT and DELETE
+// /decks/{deck}/questions/{index}
+func (server *Server) deckQuestions(request *http.Request) (int, interface{}, error) {
+	parts := pathParts(request.URL.Path, "/decks/")
+	if len(parts) < 2 || len(parts) > 3 || parts[1] != "questions" {
+		return 0, nil, newError(http.StatusNotFound, "Not found: %s", request.URL.Path)
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	file, err := server.deckFile(parts[0])
+	if err != nil {
+		return 0, nil, err
+	}
+	questions, err := quiz.ReadDeck(file)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if len(parts) == 2 {
+		switch request.Method {
+		case http.MethodGet:
+			deckQuestions := []DeckQuestion{}
+			for index, question := range questions {
+				deckQuestions = append(deckQuestions, DeckQuestion{Index: index, Question: question})
+			}
+			return http.StatusOK, deckQuestions, nil
+		case http.MethodPost:
+			question, err := readQuestion(request)
+			if err != nil {
+				return 0, nil, err
+			}
+			err = quiz.WriteDeck(file, append(questions, question))
+			if err != nil {
+				return 0, nil, err
+			}
+			return http.StatusCreated, DeckQuestion{Index: len(questions), Question: question}, nil
+		}
+		return 0, nil, methodNotAllowed(request)
+	}
+
+	index, err := strconv.Atoi(parts[2])
+	if err != nil || index < 0 || index >= len(questions) {
+		return 0, nil, newError(http.StatusNotFound, "Deck '%s' has no question %s", parts[0], parts[2])
+	}
+
+	switch request.Method {
+	case http.MethodGet:
+		return http.StatusOK, DeckQuestion{Index: index, Question: questions[index]}, nil
+	case http.MethodPut:
+		question, err := readQuestion(request)
+		if err != nil {
+			return 0, nil, err
+		}
+		questions[index] = question
+		err = quiz.WriteDeck(file, questions)
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusOK, DeckQuestion{Index: index, Question: question}, nil
+	case http.MethodDelete:
+		questions = append(questions[:index], questions[index+1:]...)
+		err = quiz.WriteDeck(file, questions)
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusNoContent, nil, nil
+	}
+	return 0, nil, methodNotAllowed(request)
+}
+
+// POST /sessions starts a single-player game on one deck or all decks
+func (server *Server) createSession(request *http.Request) (int, interface{}, error) {
+	if request.Method != http.MethodPost {
+		return 0, nil, methodNotAllowed(request)
+	}
+
+	var body sessionRequest
+	err := decodeJSON(request, &body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if body.Amount < 0 {
+		return 0, nil, newError(http.StatusBadRequest, "The amount must not be negative")
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.expireSessions(time.Now())
+
+	files := server.configuration.Decks
+	if body.Deck != "" {
+		file, err := server.deckFile(body.Deck)
+		if err != nil {
+			return 0, nil, err
+		}
+		files = []string{file}
+	}
+	questions, err := quiz.ReadDecks(files)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(questions) == 0 {
+		return 0, nil, newError(http.StatusConflict, "There are no questions to play")
+	}
+	if body.Amount > 0 && body.Amount < len(questions) {
+		questions = questions[:body.Amount]
+	}
+
+	_, err = server.profiles.Ensure(body.Player)
+	if err != nil {
+		return 0, nil, newError(http.StatusBadRequest, "%s", err.Error())
+	}
+
+	session, err := quiz.NewSession(server.quiz, body.Player, server.configuration, questions)
+	if err != nil {
+		return 0, nil, err
+	}
+	server.sessions[session.ID()] = &serverSession{Session: session, used: time.Now()}
+
+	return http.StatusCreated, sessionState(session), nil
+}
+
+// GET /sessions/{id} and POST /sessions/{id}/answers
+func (server *Server) session(request *http.Request) (int, interface{}, error) {
+	parts := pathParts(request.URL.Path, "/sessions/")
+	if len(parts) < 1 || len(parts) > 2 || len(parts) == 2 && parts[1] != "answers" {
+		return 0, nil, newError(http.StatusNotFound, "Not found: %s", request.URL.Path)
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	now := time.Now()
+	server.expireSessions(now)
+
+	stored, found := server.sessions[parts[0]]
+	if !found {
+		return 0, nil, newError(http.StatusNotFound, "No session with ID '%s'", parts[0])
+	}
+	stored.used = now
+	session := stored.Session
+
+	if len(parts) == 1 {
+		if request.Method != http.MethodGet {
+			return 0, nil, methodNotAllowed(request)
+		}
+		return http.StatusOK, sessionState(session), nil
+	}
+
+	if request.Method != http.MethodPost {
+		return 0, nil, methodNotAllowed(request)
+	}
+	var body answerRequest
+	err := decodeJSON(request, &body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if session.Finished() {
+		return 0, nil, newError(http.StatusConflict, "The game is over")
+	}
+	answer, err := session.Answer(body.Choice)
+	if err != nil {
+		return 0, nil, newError(http.StatusBadRequest, "%s", err.Error())
+	}
+	if session.Finished() {
+		err = server.history.Append(session.Record())
+		if err != nil {
+			return 0, nil, err
+		}
+		// The finished game lives on in the history
+		delete(server.sessions, session.ID())
+	}
+
+	return http.StatusOK, AnswerResult{
+		Correct:     answer.Correct,
+		Answer:      answer.Answer,
+		RightAnswer: answer.Question.RightAnswer,
+		Explanation: answer.Question.Explanation,
+		Source:      answer.Question.Source,
+		Session:     sessionState(session),
+	}, nil
+}
+
+// Drops the sessions that have not been used for sessionIdleTimeout. Call it
+// with the mutex held.
+func (server *Server) expireSessions(now time.Time) {
+	for id, session := range server.sessions {
+		if now.Sub(session.used) > sessionIdleTimeout {
+			delete(server.sessions, id)
+		}
+	}
+}
+
+// GET /history?player=NAME&limit=N lists the most recent games, oldest first
+func (server *Server) gameHistory(request *http.Request) (int, interface{}, error) {
+	if request.Method != http.MethodGet {
+		return 0, nil, methodNotAllowed(request)
+	}
+	query := request.URL.Query()
+	limit, err := intParameter(query.Get("limit"), 20)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	records, err := server.history.PlayerRecords(query.Get("player"))
+	if err != nil {
+		return 0, nil, err
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	if records == nil {
+		records = []quiz.GameRecord{}
+	}
+
+	return http.StatusOK, records, nil
+}
+
+// GET /history/{id}
+func (server *Server) game(request *http.Request) (int, interface{}, error) {
+	if request.Method != http.MethodGet {
+		return 0, nil, methodNotAllowed(request)
+	}
+	parts := pathParts(request.URL.Path, "/history/")
+	if len(parts) != 1 {
+		return 0, nil, newError(http.StatusNotFound, "Not found: %s", request.URL.Path)
+	}
+
+	record, err := server.history.Find(parts[0])
+	if err != nil {
+		return 0, nil, newError(http.StatusNotFound, "%s", err.Error())
+	}
+
+	return http.StatusOK, record, nil
+}
+
+// GET /leaderboard with the same filters as the leaderboard command
+func (server *Server) leaderboard(request *http.Request) (int, interface{}, error) {
+	if request.Method != http.MethodGet {
+		return 0, nil, methodNotAllowed(request)
+	}
+	query := request.URL.Query()
+	limit, err := intParameter(query.Get("limit"), 10)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	records, err := server.history.Records()
+	if err != nil {
+		return 0, nil, err
+	}
+	entries, err := quiz.Leaderboard(records, quiz.LeaderboardFilter{
+		Category:   query.Get("category"),
+		Difficulty: query.Get("difficulty"),
+		Mode:       query.Get("mode"),
+		Window:     query.Get("window"),
+		Now:        time.Now(),
+	}, limit)
+	if err != nil {
+		return 0, nil, newError(http.StatusBadRequest, "%s", err.Error())
+	}
+	if entries == nil {
+		entries = []quiz.LeaderboardEntry{}
+	}
+
+	return http.StatusOK, entries, nil
+}
+
+// Returns the file of the configured deck with the given name
+func (server *Server) deckFile(name string) (string, error) {
+	for _, file := range server.configuration.Decks {
+		if deckName(file) == name {
+			return file, nil
+		}
+	}
+	return "", newError(http.StatusNotFound, "No deck named '%s'", name)
+}
+
+func deckName(file string) string {
+	return strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+}
+
+func sessionState(session *quiz.Session) SessionState {
+	state := SessionState{
+		ID:              session.ID(),
+		Player:          session.Player(),
+		Number:          session.Number(),
+		NumberQuestions: session.NumberQuestions(),
+		CorrectAnswers:  session.CorrectAnswers(),
+		Finished:        session.Finished(),
+	}
+	question, answerMap, found := session.Current()
+	if found {
+		state.Question = &SessionQuestion{Question: question.Question, Content: question.RichContent(), Options: answerMap}
+	} else {
+		state.Number = session.NumberQuestions()
+	}
+
+	return state
+}
+
+func readQuestion(request *http.Request) (quiz.Question, error) {
+	var question quiz.Question
+	err := decodeJSON(request, &question)
+	if err != nil {
+		return question, err
+	}
+	err = quiz.ValidateQuestion(question)
+	if err != nil {
+		return question, newError(http.StatusBadRequest, "%s", err.Error())
+	}
+
+	return question, nil
+}
+
+func decodeJSON(request *http.Request, value interface{}) error {
+	err := json.NewDecoder(request.Body).Decode(value)
+	if err != nil {
+		return newError(http.StatusBadRequest, "Invalid JSON: %s", err.Error())
+	}
+	return nil
+}
+
+func intParameter(value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, newError(http.StatusBadRequest, "Invalid number '%s'", value)
+	}
+	return number, nil
+}
+
+// Returns the non-empty path segments after the prefix
+func pathParts(path string, prefix string) []string {
+	var parts []string
+	for _, part := range strings.Split(strings.TrimPrefix(path, prefix), "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+func methodNotAllowed(request *http.Request) error {
+	return newError(http.StatusMethodNotAllowed, "Method %s is not allowed on %s", request.Method, request.URL.Path)
+}
+
+func writeJSON(writer http.ResponseWriter, status int, value interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(value)
+}
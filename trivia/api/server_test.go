@@ -0,0 +1,233 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+	"trivia/quiz"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Keeps the answers in order, so the right answer is always option 4
+type noShuffle struct{}
+
+func (noShuffle) Shuffle(n int, swap func(i, j int)) {}
+
+var goQuestion = quiz.Question{
+	Category:     "Go",
+	Question:     "Which language is this written in?",
+	RightAnswer:  "Go",
+	WrongAnswers: [3]string{"Python", "Java", "Ruby"},
+}
+
+var colorQuestion = quiz.Question{
+	Question:     "What is blue and yellow together?",
+	RightAnswer:  "Green",
+	WrongAnswers: [3]string{"Red", "Black", "Pink"},
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, quiz.Configuration) {
+	dataDir := t.TempDir()
+	configuration := quiz.Configuration{
+		Decks:   []string{filepath.Join(dataDir, "go.json"), filepath.Join(dataDir, "colors.json")},
+		DataDir: dataDir,
+	}
+	assert.NoError(t, quiz.WriteDeck(configuration.Decks[0], []quiz.Question{goQuestion}))
+	assert.NoError(t, quiz.WriteDeck(configuration.Decks[1], []quiz.Question{colorQuestion}))
+
+	server := httptest.NewServer(NewServer(quiz.NewQuizWithShuffler(noShuffle{}), configuration).Handler())
+	t.Cleanup(server.Close)
+
+	return server, configuration
+}
+
+// Sends the request and decodes the JSON response into result
+func call(t *testing.T, server *httptest.Server, method string, path string, body interface{}, result interface{}) int {
+	var requestBody bytes.Buffer
+	if body != nil {
+		assert.NoError(t, json.NewEncoder(&requestBody).Encode(body))
+	}
+	request, err := http.NewRequest(method, server.URL+path, &requestBody)
+	assert.NoError(t, err)
+	response, err := server.Client().Do(request)
+	assert.NoError(t, err)
+	defer response.Body.Close()
+
+	if result != nil {
+		assert.NoError(t, json.NewDecoder(response.Body).Decode(result))
+	}
+	return response.StatusCode
+}
+
+func TestDecks(t *testing.T) {
+	server, configuration := newTestServer(t)
+
+	var decks []Deck
+	assert.Equal(t, http.StatusOK, call(t, server, "GET", "/decks", nil, &decks))
+	assert.Equal(t, []Deck{
+		{Name: "go", File: configuration.Decks[0], Questions: 1},
+		{Name: "colors", File: configuration.Decks[1], Questions: 1},
+	}, decks)
+
+	var failure errorResponse
+	assert.Equal(t, http.StatusMethodNotAllowed, call(t, server, "POST", "/decks", nil, &failure))
+	assert.Equal(t, "Method POST is not allowed on /decks", failure.Error)
+}
+
+func TestDeckQuestions(t *testing.T) {
+	server, configuration := newTestServer(t)
+
+	var questions []DeckQuestion
+	assert.Equal(t, http.StatusOK, call(t, server, "GET", "/decks/go/questions", nil, &questions))
+	assert.Equal(t, []DeckQuestion{{Index: 0, Question: goQuestion}}, questions)
+
+	var created DeckQuestion
+	assert.Equal(t, http.StatusCreated, call(t, server, "POST", "/decks/go/questions", colorQuestion, &created))
+	assert.Equal(t, DeckQuestion{Index: 1, Question: colorQuestion}, created)
+
+	updated := colorQuestion
+	updated.RightAnswer = "Green!"
+	var question DeckQuestion
+	assert.Equal(t, http.StatusOK, call(t, server, "PUT", "/decks/go/questions/1", updated, &question))
+	assert.Equal(t, http.StatusOK, call(t, server, "GET", "/decks/go/questions/1", nil, &question))
+	assert.Equal(t, "Green!", question.RightAnswer)
+
+	assert.Equal(t, http.StatusNoContent, call(t, server, "DELETE", "/decks/go/questions/0", nil, nil))
+	deck, err := quiz.ReadDeck(configuration.Decks[0])
+	assert.NoError(t, err)
+	assert.Equal(t, []quiz.Question{updated}, deck)
+}
+
+func TestDeckQuestionErrors(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var failure errorResponse
+	assert.Equal(t, http.StatusNotFound, call(t, server, "GET", "/decks/history/questions", nil, &failure))
+	assert.Equal(t, "No deck named 'history'", failure.Error)
+
+	assert.Equal(t, http.StatusNotFound, call(t, server, "GET", "/decks/go/questions/5", nil, &failure))
+	assert.Equal(t, "Deck 'go' has no question 5", failure.Error)
+
+	assert.Equal(t, http.StatusNotFound, call(t, server, "GET", "/decks/go", nil, &failure))
+
+	invalid := goQuestion
+	invalid.WrongAnswers[0] = ""
+	assert.Equal(t, http.StatusBadRequest, call(t, server, "POST", "/decks/go/questions", invalid, &failure))
+	assert.Equal(t, "The question needs a correct answer and three incorrect answers", failure.Error)
+
+	assert.Equal(t, http.StatusBadRequest, call(t, server, "PUT", "/decks/go/questions/0", "text", &failure))
+	assert.Contains(t, failure.Error, "Invalid JSON")
+}
+
+func TestIdleSessionsExpire(t *testing.T) {
+	now := time.Now()
+	server := NewServer(quiz.NewQuizWithShuffler(noShuffle{}), quiz.Configuration{})
+	server.sessions["idle"] = &serverSession{used: now.Add(-sessionIdleTimeout - time.Second)}
+	server.sessions["active"] = &serverSession{used: now.Add(-time.Minute)}
+
+	server.expireSessions(now)
+
+	assert.Len(t, server.sessions, 1)
+	assert.Contains(t, server.sessions, "active")
+}
+
+func TestSessionIsPlayedAndRecorded(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var session SessionState
+	assert.Equal(t, http.StatusCreated, call(t, server, "POST", "/sessions", sessionRequest{Player: "anna"}, &session))
+	assert.Equal(t, "anna", session.Player)
+	assert.Equal(t, 1, session.Number)
+	assert.Equal(t, 2, session.NumberQuestions)
+	assert.Equal(t, goQuestion.Question, session.Question.Question)
+	assert.Equal(t, "Go", session.Question.Options["4"])
+
+	var failure errorResponse
+	assert.Equal(t, http.StatusBadRequest, call(t, server, "POST", "/sessions/"+session.ID+"/answers", answerRequest{Choice: "9"}, &failure))
+	assert.Equal(t, "The specified answer is invalid answer: 9", failure.Error)
+
+	var result AnswerResult
+	assert.Equal(t, http.StatusOK, call(t, server, "POST", "/sessions/"+session.ID+"/answers", answerRequest{Choice: "4"}, &result))
+	assert.True(t, result.Correct)
+	assert.Equal(t, 2, result.Session.Number)
+	assert.Equal(t, colorQuestion.Question, result.Session.Question.Question)
+
+	var lastResult AnswerResult
+	assert.Equal(t, http.StatusOK, call(t, server, "POST", "/sessions/"+session.ID+"/answers", answerRequest{Choice: "1"}, &lastResult))
+	assert.False(t, lastResult.Correct)
+	assert.Equal(t, "Red", lastResult.Answer)
+	assert.Equal(t, "Green", lastResult.RightAnswer)
+	assert.True(t, lastResult.Session.Finished)
+	assert.Nil(t, lastResult.Session.Question)
+	assert.Equal(t, 1, lastResult.Session.CorrectAnswers)
+
+	// Finished sessions are only kept in the history
+	assert.Equal(t, http.StatusNotFound, call(t, server, "POST", "/sessions/"+session.ID+"/answers", answerRequest{Choice: "1"}, &failure))
+	assert.Equal(t, http.StatusNotFound, call(t, server, "GET", "/sessions/"+session.ID, nil, &failure))
+
+	var records []quiz.GameRecord
+	assert.Equal(t, http.StatusOK, call(t, server, "GET", "/history?player=anna", nil, &records))
+	assert.Len(t, records, 1)
+	assert.Equal(t, session.ID, records[0].ID)
+	assert.Equal(t, 1, records[0].CorrectAnswers)
+
+	var record quiz.GameRecord
+	assert.Equal(t, http.StatusOK, call(t, server, "GET", "/history/"+session.ID, nil, &record))
+	assert.Equal(t, 2, record.NumberQuestions)
+
+	var entries []quiz.LeaderboardEntry
+	assert.Equal(t, http.StatusOK, call(t, server, "GET", "/leaderboard?window=week", nil, &entries))
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "anna", entries[0].Player)
+	assert.Equal(t, 1, entries[0].Rank)
+}
+
+func TestSessionOptions(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var session SessionState
+	assert.Equal(t, http.StatusCreated, call(t, server, "POST", "/sessions", sessionRequest{Player: "bert", Deck: "colors", Amount: 5}, &session))
+	assert.Equal(t, 1, session.NumberQuestions)
+	assert.Equal(t, colorQuestion.Question, session.Question.Question)
+
+	var failure errorResponse
+	assert.Equal(t, http.StatusBadRequest, call(t, server, "POST", "/sessions", sessionRequest{}, &failure))
+	assert.Equal(t, "Player name must not be empty", failure.Error)
+	assert.Equal(t, http.StatusNotFound, call(t, server, "POST", "/sessions", sessionRequest{Player: "bert", Deck: "nope"}, &failure))
+	assert.Equal(t, http.StatusNotFound, call(t, server, "GET", "/sessions/unknown", nil, &failure))
+	assert.Equal(t, "No session with ID 'unknown'", failure.Error)
+}
+
+func TestHistoryAndLeaderboardErrors(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var records []quiz.GameRecord
+	assert.Equal(t, http.StatusOK, call(t, server, "GET", "/history", nil, &records))
+	assert.Empty(t, records)
+
+	var failure errorResponse
+	assert.Equal(t, http.StatusNotFound, call(t, server, "GET", "/history/abc", nil, &failure))
+	assert.Equal(t, "No game with ID 'abc'", failure.Error)
+	assert.Equal(t, http.StatusBadRequest, call(t, server, "GET", "/history?limit=x", nil, &failure))
+	assert.Equal(t, http.StatusBadRequest, call(t, server, "GET", "/leaderboard?window=year", nil, &failure))
+}
+
+func TestOpenAPI(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	var description struct {
+		OpenAPI string                 `json:"openapi"`
+		Paths   map[string]interface{} `json:"paths"`
+	}
+	assert.Equal(t, http.StatusOK, call(t, server, "GET", "/openapi.json", nil, &description))
+	assert.Equal(t, "3.0.3", description.OpenAPI)
+	for _, path := range []string{"/decks", "/decks/{deck}/questions", "/decks/{deck}/questions/{index}",
+		"/sessions", "/sessions/{id}", "/sessions/{id}/answers", "/history", "/history/{id}", "/leaderboard"} {
+		assert.Contains(t, description.Paths, path)
+	}
+}
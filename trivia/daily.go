@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+	"trivia/quiz"
+)
+
+func daily(args []string, stdin io.Reader) error {
+	showLeaderboard := len(args) > 0 && args[0] == "leaderboard"
+	if showLeaderboard {
+		args = args[1:]
+	}
+
+	flags := flag.NewFlagSet("daily", flag.ExitOnError)
+	player := flags.String("player", defaultPlayer(), "Name of the player")
+	date := flags.String("date", "", "Day of the leaderboard as YYYY-MM-DD (default: today)")
+	language := flags.String("lang", "", "Language of the game, e.g. sv (default: from LANG)")
+	accessible := flags.Bool("accessible", false, "Linear output for screen readers (default: from the configuration)")
+	flags.Usage = func() {
+		fmt.Println("Usage: trivia daily [-player NAME] [-lang LANGUAGE] [-accessible]")
+		fmt.Println("       trivia daily leaderboard [-date YYYY-MM-DD] [-lang LANGUAGE]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	localizer, err := quiz.SelectLocalizer(*language)
+	if err != nil {
+		return err
+	}
+	configuration, err := (&quiz.Quiz{}).ReadConfigurationFromYAML(configFile)
+	if err != nil {
+		return err
+	}
+	dataDir := quiz.DataDir(configuration)
+	record := quiz.NewDailyRecord(dataDir)
+
+	today := time.Now()
+	if showLeaderboard {
+		day := today.Format("2006-01-02")
+		if *date != "" {
+			day = *date
+		}
+		results, err := record.Results(day)
+		if err != nil {
+			return err
+		}
+		fmt.Print(quiz.FormatDailyLeaderboard(day, results, localizer))
+		return nil
+	}
+
+	deckFiles := configuration.Decks
+	if len(deckFiles) == 0 {
+		deckFiles = []string{configuration.QuestionFile}
+	}
+	questions, err := quiz.ReadDecks(deckFiles)
+	if err != nil {
+		return err
+	}
+	challenge, err := quiz.NewDailyChallenge(questions, today, configuration.Daily.Amount)
+	if err != nil {
+		return err
+	}
+	// Every language gets the same questions, translated where the decks can
+	challenge.Questions = quiz.TranslateQuestions(challenge.Questions, localizer.Language())
+
+	_, err = quiz.NewProfiles(dataDir).Ensure(*player)
+	if err != nil {
+		return err
+	}
+	err = record.Start(challenge.Date, *player)
+	if err != nil {
+		return err
+	}
+
+	// The answer order comes from the seed of the day as well
+	quizGame := quiz.NewQuiz(challenge.Seed)
+	quizGame.SetLocalizer(localizer)
+	options := quiz.Options{Player: *player, Localizer: localizer}
+	if accessibleMode(flags, *accessible, configuration) {
+		quizGame.SetAccessible(true)
+		options.Renderer = quiz.NewAccessibleRenderer(os.Stdout, localizer)
+	}
+
+	// Ctrl-C ends the challenge like the quit command, with the score so far
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	options.Interrupt = interrupt
+
+	game, err := quiz.PlayDaily(quizGame, stdin, challenge, options)
+	if err == quiz.ErrQuit {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	game.Configuration = configuration
+	err = record.Finish(challenge.Date, game)
+	if err != nil {
+		return err
+	}
+	err = quiz.NewHistory(dataDir).Append(game)
+	if err != nil {
+		return err
+	}
+
+	results, err := record.Results(challenge.Date)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Print(quiz.FormatDailyLeaderboard(challenge.Date, results, localizer))
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"trivia/quiz"
+)
+
+const generatorGodoc string = "godoc"
+
+func gen(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Usage: trivia gen %s [-out FILE] PACKAGE", generatorGodoc)
+	}
+	if args[0] != generatorGodoc {
+		return fmt.Errorf("Unknown generator '%s', use %s", args[0], generatorGodoc)
+	}
+
+	flags := flag.NewFlagSet("gen godoc", flag.ExitOnError)
+	out := flags.String("out", "", "Deck file to write (default: resources/decks/PACKAGE.json)")
+	flags.Parse(args[1:])
+	if flags.NArg() != 1 {
+		return fmt.Errorf("Usage: trivia gen %s [-out FILE] PACKAGE, e.g. net/http", generatorGodoc)
+	}
+	importPath := flags.Arg(0)
+
+	docPackage, err := quiz.ReadPackageDoc(importPath)
+	if err != nil {
+		return err
+	}
+	questions := quiz.GodocQuestions(docPackage)
+	if len(questions) == 0 {
+		return fmt.Errorf("Found no documented functions in %s to ask about", importPath)
+	}
+
+	deckFile := *out
+	if deckFile == "" {
+		deckFile = filepath.Join("resources", "decks", strings.ReplaceAll(importPath, "/", "_")+".json")
+	}
+	err = quiz.WriteDeck(deckFile, questions)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d questions about %s to %s, add it to 'decks' in %s to play it\n", len(questions), importPath, deckFile, configFile)
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"trivia/quiz"
+)
+
+func history(args []string) error {
+	flags := flag.NewFlagSet("history", flag.ExitOnError)
+	player := flags.String("player", "", "Only list the games of this player")
+	limit := flags.Int("limit", 20, "Number of most recent games to list (0 lists all)")
+	flags.Usage = func() {
+		fmt.Println("Usage: trivia history [-player NAME] [-limit N]")
+		fmt.Println("       trivia history show GAME_ID")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	configuration, err := (&quiz.Quiz{}).ReadConfigurationFromYAML(configFile)
+	if err != nil {
+		return err
+	}
+	gameHistory := quiz.NewHistory(quiz.DataDir(configuration))
+
+	if flags.Arg(0) == "show" {
+		if flags.NArg() != 2 {
+			flags.Usage()
+			return fmt.Errorf("Missing game ID")
+		}
+		record, err := gameHistory.Find(flags.Arg(1))
+		if err != nil {
+			return err
+		}
+		fmt.Print(quiz.FormatGame(record))
+		return nil
+	}
+
+	records, err := gameHistory.PlayerRecords(*player)
+	if err != nil {
+		return err
+	}
+	if *limit > 0 && len(records) > *limit {
+		records = records[len(records)-*limit:]
+	}
+	fmt.Print(quiz.FormatGameList(records))
+
+	return nil
+}
+
+func profile(args []string) error {
+	configuration, err := (&quiz.Quiz{}).ReadConfigurationFromYAML(configFile)
+	if err != nil {
+		return err
+	}
+	profiles := quiz.NewProfiles(quiz.DataDir(configuration))
+
+	if len(args) == 2 && args[0] == "add" {
+		localizer, err := quiz.SelectLocalizer("")
+		if err != nil {
+			return err
+		}
+		created, err := profiles.Ensure(args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Println(localizer.Text("profile_created", created.Name, created.Created.Format("2006-01-02"), profiles.File))
+		return nil
+	}
+	if len(args) > 0 && args[0] != "list" {
+		return fmt.Errorf("Usage: trivia profile [list | add NAME]")
+	}
+
+	list, err := profiles.List()
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		fmt.Println("No profiles.")
+	}
+	for _, player := range list {
+		fmt.Printf("%-20s created %s\n", player.Name, player.Created.Format("2006-01-02"))
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+	"trivia/quiz"
+)
+
+func leaderboard(args []string) error {
+	flags := flag.NewFlagSet("leaderboard", flag.ExitOnError)
+	category := flags.String("category", "", "Only count questions of this category (name or OpenTrivia ID)")
+	difficulty := flags.String("difficulty", "", "Only count questions of this difficulty")
+	mode := flags.String("mode", "", "Only include games of this mode (classic, rounds, adaptive, hotseat, team, tournament, daily)")
+	window := flags.String("window", "all", "Time window: today, week or all")
+	limit := flags.Int("limit", 10, "Number of players to show (0 shows all)")
+	flags.Parse(args)
+
+	configuration, err := (&quiz.Quiz{}).ReadConfigurationFromYAML(configFile)
+	if err != nil {
+		return err
+	}
+	records, err := quiz.NewHistory(quiz.DataDir(configuration)).Records()
+	if err != nil {
+		return err
+	}
+
+	entries, err := quiz.Leaderboard(records, quiz.LeaderboardFilter{
+		Category:   *category,
+		Difficulty: *difficulty,
+		Mode:       *mode,
+		Window:     *window,
+		Now:        time.Now(),
+	}, *limit)
+	if err != nil {
+		return err
+	}
+	fmt.Print(quiz.FormatLeaderboard(entries))
+
+	return nil
+}
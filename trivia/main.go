@@ -1,12 +1,105 @@
 package main
 
 import (
+	"bufio"
+	"flag"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 	"trivia/quiz"
 )
 
+const configFile string = "resources/config.yaml"
+
 func main() {
-	stdin := os.Stdin
-	quizGame := &quiz.Quiz{}
-	quiz.Run(quizGame, stdin)
+	command := "play"
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command = args[0]
+		args = args[1:]
+	}
+
+	// A single buffered reader keeps piped input from being lost between answers
+	stdin := bufio.NewReader(os.Stdin)
+
+	var err error
+	switch command {
+	case "play":
+		err = play(args, stdin)
+	case "study":
+		err = study(args, stdin)
+	case "history":
+		err = history(args)
+	case "profile":
+		err = profile(args)
+	case "leaderboard":
+		err = leaderboard(args)
+	case "stats":
+		err = stats(args)
+	case "serve":
+		err = serve(args)
+	case "join":
+		err = join(args, stdin)
+	case "web":
+		err = webServer(args)
+	case "api":
+		err = apiServer(args)
+	case "tournament":
+		err = tournament(args, stdin)
+	case "replay":
+		err = replay(args)
+	case "daily":
+		err = daily(args, stdin)
+	case "resume":
+		err = resume(args, stdin)
+	case "gen":
+		err = gen(args)
+	default:
+		err = fmt.Errorf("Unknown command '%s'. Available commands: play, study, history, profile, leaderboard, stats, serve, join, web, api, tournament, replay, daily, resume, gen", command)
+	}
+
+	// On stderr, to keep stdout to the game, e.g. the JSON lines of -protocol
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err.Error())
+		os.Exit(1)
+	}
+}
+
+// Returns the player name from the environment when none is given
+func defaultPlayer() string {
+	for _, variable := range []string{"USER", "USERNAME"} {
+		if player := os.Getenv(variable); player != "" {
+			return player
+		}
+	}
+	return "player"
+}
+
+// Returns whether to play in the accessible mode, as given with -accessible or
+// else as configured
+func accessibleMode(flags *flag.FlagSet, accessible bool, configuration quiz.Configuration) bool {
+	if flagGiven(flags, "accessible") {
+		return accessible
+	}
+	return configuration.Accessibility.Enabled
+}
+
+// Returns whether the flag was given on the command line
+func flagGiven(flags *flag.FlagSet, name string) bool {
+	given := false
+	flags.Visit(func(option *flag.Flag) {
+		if option.Name == name {
+			given = true
+		}
+	})
+	return given
+}
+
+// Returns a quiz that shuffles from the seed, or from a new seed when it is 0
+func newQuiz(seed int64) (*quiz.Quiz, int64) {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return quiz.NewQuiz(seed), seed
 }
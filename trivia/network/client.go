@@ -0,0 +1,122 @@
+package network
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"trivia/quiz"
+)
+
+// Connects to a match as the given player, printing the events of the match
+// in the language of the localizer, as linear text for screen readers when
+// accessible is true, and sending every line read from stdin as an answer.
+// Returns when the match is over or the connection is closed.
+func Join(address string, player string, accessible bool, localizer *quiz.Localizer, stdin io.Reader, stdout io.Writer) error {
+	connection, err := net.Dial("tcp", address)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	encoder := json.NewEncoder(connection)
+	err = encoder.Encode(quiz.Message{Type: quiz.MessageJoin, Player: player})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		reader := bufio.NewReader(stdin)
+		for {
+			line, err := reader.ReadString('\n')
+			choice := strings.TrimSpace(line)
+			if choice != "" {
+				if encoder.Encode(quiz.Message{Type: quiz.MessageAnswer, Choice: choice}) != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	decoder := json.NewDecoder(connection)
+	for {
+		var event quiz.Event
+		err = decoder.Decode(&event)
+		if err == io.EOF {
+			return fmt.Errorf("The server closed the connection")
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(stdout, FormatEvent(event, player, accessible, localizer))
+		if event.Type == quiz.EventGameOver {
+			return nil
+		}
+	}
+}
+
+// Formats an event of the match for the terminal of the given player, in the
+// language of the localizer. The accessible format announces each question
+// and names every option.
+func FormatEvent(event quiz.Event, player string, accessible bool, localizer *quiz.Localizer) string {
+	switch event.Type {
+	case quiz.EventLobby:
+		return localizer.Text("players", strings.Join(event.Players, ", ")) + "\n"
+	case quiz.EventQuestion:
+		// The content blocks, when given, hold the question text as well
+		question := quiz.Question{Question: event.Question}
+		if len(event.Content) > 0 {
+			question = quiz.Question{Content: event.Content}
+		}
+		formatter := &quiz.Quiz{}
+		formatter.SetLocalizer(localizer)
+		formatter.SetAccessible(accessible)
+		text := formatter.FormatQuestion(question, event.Options)
+		if accessible {
+			return fmt.Sprintf("\n%s%s\n",
+				localizer.Text("accessible_timed_question", event.Number, event.NumberQuestions, event.TimeLimitSeconds), text)
+		}
+		return fmt.Sprintf("\n%d/%d (%ds)%s\n", event.Number, event.NumberQuestions, event.TimeLimitSeconds, text)
+	case quiz.EventAnswer:
+		if event.Player == player {
+			return localizer.Text("answer_received") + "\n"
+		}
+		return localizer.Text("player_answered", event.Player) + "\n"
+	case quiz.EventReveal:
+		var builder strings.Builder
+		builder.WriteString(localizer.Text("reveal", event.RightAnswer) + "\n")
+		if event.Explanation != "" {
+			builder.WriteString(event.Explanation + "\n")
+		}
+		if event.Source != "" {
+			builder.WriteString(localizer.Text("source", event.Source) + "\n")
+		}
+		for _, result := range event.Results {
+			verdict := localizer.Text("wrong")
+			if result.Correct {
+				verdict = localizer.Text("correct")
+			}
+			builder.WriteString(fmt.Sprintf("  %s: %s (%s)\n", result.Player, result.Answer, verdict))
+		}
+		return builder.String()
+	case quiz.EventScoreboard:
+		var builder strings.Builder
+		builder.WriteString(localizer.Text("scores"))
+		for _, score := range event.Scores {
+			builder.WriteString(fmt.Sprintf(" %s %d", score.Name, score.CorrectAnswers))
+		}
+		return builder.String() + "\n"
+	case quiz.EventGameOver:
+		return "\n" + quiz.FormatRanking(event.Scores, localizer)
+	case quiz.EventError:
+		return localizer.Text("error", event.Message) + "\n"
+	}
+
+	return ""
+}
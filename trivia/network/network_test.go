@@ -0,0 +1,176 @@
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"trivia/quiz"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Keeps the answers in order, so the right answer is always option 4
+type noShuffle struct{}
+
+func (noShuffle) Shuffle(n int, swap func(i, j int)) {}
+
+var testQuestions = []quiz.Question{
+	{Question: "Which language is this written in?", RightAnswer: "Go", WrongAnswers: [3]string{"Python", "Java", "Ruby"}},
+}
+
+func startServer(t *testing.T, timeLimit time.Duration) (*quiz.Match, *Server, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	match := quiz.NewMatch(quiz.NewQuizWithShuffler(noShuffle{}), testQuestions, timeLimit)
+	server := NewServer(match)
+	go server.Serve(listener)
+
+	return match, server, listener.Addr().String()
+}
+
+// Writer that answers through stdin every time a question is printed
+type answeringWriter struct {
+	mutex  sync.Mutex
+	output bytes.Buffer
+	stdin  io.Writer
+	answer string
+}
+
+func (writer *answeringWriter) Write(data []byte) (int, error) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	writer.output.Write(data)
+	if strings.Contains(string(data), "Answer: ") {
+		go writer.stdin.Write([]byte(writer.answer + "\n"))
+	}
+	return len(data), nil
+}
+
+func (writer *answeringWriter) String() string {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+
+	return writer.output.String()
+}
+
+func TestServeAndJoin(t *testing.T) {
+	match, server, address := startServer(t, 5*time.Second)
+
+	stdinReader, stdinWriter := io.Pipe()
+	stdout := &answeringWriter{stdin: stdinWriter, answer: "4"}
+	joined := make(chan error)
+	go func() { joined <- Join(address, "anna", false, nil, stdinReader, stdout) }()
+
+	assert.True(t, match.WaitForPlayers(1, nil))
+	scores := match.Play()
+	match.Close()
+	server.Wait()
+
+	assert.NoError(t, <-joined)
+	assert.Equal(t, 1, scores[0].CorrectAnswers)
+	output := stdout.String()
+	assert.Contains(t, output, "Players: anna\n")
+	assert.Contains(t, output, "Question: Which language is this written in?\n")
+	assert.Contains(t, output, "  anna: Go (correct)\n")
+	assert.Contains(t, output, "Final ranking:\n  1. anna: 1 of 1 correct answer")
+}
+
+func TestServeProtocol(t *testing.T) {
+	match, server, address := startServer(t, 5*time.Second)
+
+	connection, err := net.Dial("tcp", address)
+	assert.NoError(t, err)
+	defer connection.Close()
+	encoder := json.NewEncoder(connection)
+	decoder := json.NewDecoder(connection)
+
+	encoder.Encode(quiz.Message{Type: quiz.MessageJoin, Player: "bert"})
+	var event quiz.Event
+	decoder.Decode(&event)
+	assert.Equal(t, quiz.Event{Type: quiz.EventLobby, Players: []string{"bert"}}, event)
+
+	done := make(chan []quiz.PlayerScore)
+	go func() { done <- match.Play() }()
+
+	decoder.Decode(&event)
+	assert.Equal(t, quiz.EventQuestion, event.Type)
+	encoder.Encode(quiz.Message{Type: quiz.MessageAnswer, Choice: "9"})
+	decoder.Decode(&event)
+	assert.Equal(t, quiz.EventError, event.Type)
+	encoder.Encode(quiz.Message{Type: quiz.MessageAnswer, Choice: "1"})
+
+	scores := <-done
+	assert.Equal(t, 0, scores[0].CorrectAnswers)
+	assert.Equal(t, 1, scores[0].NumberQuestions)
+	match.Close()
+	server.Wait()
+}
+
+func TestServeRejectsDuplicatePlayer(t *testing.T) {
+	match, _, address := startServer(t, time.Second)
+	match.Join("anna")
+
+	var stdin bytes.Buffer
+	var stdout bytes.Buffer
+	err := Join(address, "anna", false, nil, &stdin, &stdout)
+
+	assert.EqualError(t, err, "The server closed the connection")
+	assert.Equal(t, "Error: Player 'anna' has already joined\n", stdout.String())
+}
+
+func TestFormatEvent(t *testing.T) {
+	assert.Equal(t, "Players: anna, bert\n", FormatEvent(quiz.Event{Type: quiz.EventLobby, Players: []string{"anna", "bert"}}, "anna", false, nil))
+	assert.Equal(t, "bert has answered.\n", FormatEvent(quiz.Event{Type: quiz.EventAnswer, Player: "bert"}, "anna", false, nil))
+	assert.Equal(t, "Answer received, waiting for the other players...\n", FormatEvent(quiz.Event{Type: quiz.EventAnswer, Player: "anna"}, "anna", false, nil))
+	assert.Equal(t, "Scores: anna 2 bert 1\n", FormatEvent(quiz.Event{Type: quiz.EventScoreboard, Scores: []quiz.PlayerScore{
+		{Name: "anna", CorrectAnswers: 2}, {Name: "bert", CorrectAnswers: 1},
+	}}, "anna", false, nil))
+	assert.Equal(t, "Error: oops\n", FormatEvent(quiz.Event{Type: quiz.EventError, Message: "oops"}, "anna", false, nil))
+	assert.Equal(t, "The correct answer is 'Go'\nIt is written in Go.\nSource: https://go.dev\n  anna: Go (correct)\n", FormatEvent(quiz.Event{
+		Type: quiz.EventReveal, RightAnswer: "Go", Explanation: "It is written in Go.", Source: "https://go.dev",
+		Results: []quiz.PlayerResult{{Player: "anna", Answer: "Go", Correct: true}},
+	}, "anna", false, nil))
+
+	question := FormatEvent(quiz.Event{
+		Type:             quiz.EventQuestion,
+		Number:           1,
+		NumberQuestions:  2,
+		Question:         "Q?",
+		Options:          map[string]string{"1": "a", "2": "b", "3": "c", "4": "d"},
+		TimeLimitSeconds: 20,
+	}, "anna", false, nil)
+	assert.Equal(t, "\n1/2 (20s)\nQuestion: Q?\n1: a\n2: b\n3: c\n4: d\nAnswer: \n", question)
+
+	question = FormatEvent(quiz.Event{
+		Type:             quiz.EventQuestion,
+		Number:           1,
+		NumberQuestions:  2,
+		Question:         "Q?",
+		Options:          map[string]string{"1": "a", "2": "b"},
+		TimeLimitSeconds: 20,
+	}, "anna", true, nil)
+	assert.Equal(t, "\nQuestion 1 of 2, 20 seconds to answer.\nQuestion: Q?\n2 options:\nOption 1: a\nOption 2: b\nAnswer: \n", question)
+
+	question = FormatEvent(quiz.Event{
+		Type:            quiz.EventQuestion,
+		Number:          2,
+		NumberQuestions: 2,
+		Question:        "What does this print?",
+		Content: []quiz.ContentBlock{
+			{Type: quiz.ContentText, Text: "What does this print?"},
+			{Type: quiz.ContentCode, Language: "go", Text: "fmt.Println(1)"},
+			{Type: quiz.ContentImage, Text: "Gopher", URL: "https://go.dev/gopher.png"},
+		},
+		Options: map[string]string{"1": "1", "2": "2", "3": "3", "4": "4"},
+	}, "anna", false, nil)
+	assert.Contains(t, question, "Question: What does this print?\n    fmt.Println(1)\n")
+	assert.Contains(t, question, "https://go.dev/gopher.png")
+}
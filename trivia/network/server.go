@@ -0,0 +1,105 @@
+package network
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"trivia/quiz"
+)
+
+// Connects players over TCP to a match
+type Server struct {
+	match       *quiz.Match
+	connections sync.WaitGroup
+}
+
+func NewServer(match *quiz.Match) *Server {
+	return &Server{match: match}
+}
+
+// Accepts players on the listener until the listener is closed
+func (server *Server) Serve(listener net.Listener) error {
+	for {
+		connection, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		server.connections.Add(1)
+		go func() {
+			defer server.connections.Done()
+			handle(connection, server.match)
+		}()
+	}
+}
+
+// Waits until all players have been sent their remaining events and
+// disconnected. Call it after closing the match.
+func (server *Server) Wait() {
+	server.connections.Wait()
+}
+
+// Serves a single player, one JSON object per line in each direction. The
+// first message must be a join message, after which answers are passed to
+// the match and match events are written back
+func handle(connection net.Conn, match *quiz.Match) {
+	defer connection.Close()
+
+	reader := bufio.NewReader(connection)
+	encoder := json.NewEncoder(connection)
+
+	var join quiz.Message
+	err := readMessage(reader, &join)
+	if err != nil || join.Type != quiz.MessageJoin {
+		encoder.Encode(quiz.Event{Type: quiz.EventError, Message: "Expected a join message"})
+		return
+	}
+
+	events, err := match.Join(join.Player)
+	if err != nil {
+		encoder.Encode(quiz.Event{Type: quiz.EventError, Message: err.Error()})
+		return
+	}
+	defer match.Leave(join.Player)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for event := range events {
+			if encoder.Encode(event) != nil {
+				break
+			}
+		}
+		// Unblocks the reader below once the match is over
+		connection.Close()
+	}()
+
+	for {
+		var message quiz.Message
+		err = readMessage(reader, &message)
+		if err != nil {
+			match.Leave(join.Player)
+			break
+		}
+		if message.Type != quiz.MessageAnswer {
+			continue
+		}
+		err = match.Answer(join.Player, message.Choice)
+		if err != nil {
+			match.SendError(join.Player, err.Error())
+		}
+	}
+	<-done
+}
+
+func readMessage(reader *bufio.Reader, message *quiz.Message) error {
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(line, message)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"trivia/quiz"
+	"trivia/tui"
+)
+
+// Collects the teams given with repeated -team flags
+type teamFlags []quiz.Team
+
+func (teams *teamFlags) String() string {
+	var names []string
+	for _, team := range *teams {
+		names = append(names, team.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (teams *teamFlags) Set(value string) error {
+	team, err := quiz.ParseTeam(value)
+	if err != nil {
+		return err
+	}
+	*teams = append(*teams, team)
+	return nil
+}
+
+func play(args []string, stdin io.Reader) error {
+	flags := flag.NewFlagSet("play", flag.ExitOnError)
+	player := flags.String("player", defaultPlayer(), "Name of the player")
+	players := flags.String("players", "", "Comma separated names of 2-8 hot-seat players")
+	hotSeat := flags.String("hotseat", quiz.HotSeatTurns, "Hot-seat mode: 'turns' to take turns, 'each' to let every player answer each question")
+	var teams teamFlags
+	flags.Var(&teams, "team", "Team as NAME=PLAYER,PLAYER (captain first), repeat for every team")
+	consensus := flags.String("consensus", quiz.ConsensusFirst, "How a team agrees on its answer: first (quickest answer), majority or captain")
+	seed := flags.Int64("seed", 0, "Seed for shuffling questions and answers, to play a game again (0 picks a new seed)")
+	recordFile := flags.String("record", "", "Record the session to this file for 'trivia replay'")
+	fullScreen := flags.Bool("tui", false, "Play in a full-screen terminal UI with arrow-key selection")
+	timeLimit := flags.Duration("time-limit", 0, "Time to answer each question, e.g. 20s (0 means no limit)")
+	output := flags.String("output", quiz.RendererAuto, "How to print the game: auto, color, nocolor, plain, accessible or jsonl")
+	accessible := flags.Bool("accessible", false, "Linear output for screen readers and a longer time limit (default: from the configuration)")
+	protocol := flags.String("protocol", "", "Drive the game over a protocol instead of the terminal: jsonl")
+	language := flags.String("lang", "", "Language of the game, e.g. sv (default: from LANG)")
+	labels := flags.String("labels", "", "Labels of the answer options: numbers or letters (default: from the configuration)")
+	flags.Parse(args)
+
+	if *protocol != "" && *protocol != quiz.RendererJSON {
+		return fmt.Errorf("Unknown protocol '%s', use %s", *protocol, quiz.RendererJSON)
+	}
+	if *protocol != "" && *fullScreen {
+		return fmt.Errorf("The full-screen mode cannot be combined with -protocol")
+	}
+
+	if *fullScreen && (*players != "" || len(teams) > 0) {
+		return fmt.Errorf("The full-screen mode is for a single player")
+	}
+
+	localizer, err := quiz.SelectLocalizer(*language)
+	if err != nil {
+		return err
+	}
+	quizGame, gameSeed := newQuiz(*seed)
+	quizGame.SetLocalizer(localizer)
+	configuration, err := quizGame.ReadConfigurationFromYAML(configFile)
+	if err != nil {
+		return err
+	}
+	if *labels == "" {
+		*labels = configuration.Labels
+	}
+	err = quizGame.SetLabels(*labels)
+	if err != nil {
+		return err
+	}
+	if accessibleMode(flags, *accessible, configuration) {
+		if *fullScreen {
+			return fmt.Errorf("The full-screen mode redraws the screen and is not accessible, play without -tui")
+		}
+		if *output != quiz.RendererAuto && *output != quiz.RendererAccessible {
+			return fmt.Errorf("The accessible mode has an output of its own, play without -output")
+		}
+		*output = quiz.RendererAccessible
+		*timeLimit = configuration.Accessibility.TimeLimit(*timeLimit)
+		quizGame.SetAccessible(true)
+	}
+
+	options := quiz.Options{
+		ConfigFile: configFile,
+		Player:     *player,
+		HotSeat:    *hotSeat,
+		Seed:       gameSeed,
+		Localizer:  localizer,
+		Labels:     *labels,
+	}
+	if *players != "" {
+		options.Player = ""
+		options.Players = strings.Split(*players, ",")
+	}
+	if len(teams) > 0 {
+		options.Player = ""
+		options.Teams = teams
+		options.Consensus = *consensus
+		options.Players = nil
+	}
+
+	dataDir := quiz.DataDir(configuration)
+	profiles := quiz.NewProfiles(dataDir)
+	names := append(options.Players, options.Player)
+	for _, team := range options.Teams {
+		names = append(names, team.Players...)
+	}
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		_, err = profiles.Ensure(name)
+		if err != nil {
+			return err
+		}
+	}
+	options.History = quiz.NewHistory(dataDir)
+	if options.Player != "" {
+		options.SaveFile = quiz.SavedGameFile(dataDir, options.Player)
+	}
+	options.Renderer, err = quiz.NewRendererByName(*output, os.Stdout, localizer)
+	if err != nil {
+		return err
+	}
+
+	var game quiz.QuizInterface = quizGame
+	if *fullScreen {
+		restore, err := tui.Open(os.Stdin, os.Stdout)
+		if err != nil {
+			return err
+		}
+		defer restore()
+		ui := tui.New(quizGame, os.Stdout, *timeLimit, localizer)
+		game, options.Renderer = ui, ui
+	} else {
+		options.TimeLimit = *timeLimit
+	}
+	if *protocol != "" {
+		game, options.Renderer = quiz.NewProtocol(quizGame, os.Stdout)
+	}
+
+	// Ctrl-C quits the game with the score so far instead of killing it
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	options.Interrupt = interrupt
+
+	if *recordFile == "" {
+		return quiz.RunWithOptions(game, stdin, options)
+	}
+
+	// Record the session even when it ends with an error, that is often when
+	// it is needed the most
+	recorder := quiz.NewRecorder(game, options)
+	recorder.SetLabels(*labels)
+	err = quiz.RunWithOptions(recorder, stdin, options)
+	recordErr := quiz.WriteRecording(*recordFile, recorder.Recording(err))
+	if err != nil {
+		return err
+	}
+	if recordErr != nil {
+		return recordErr
+	}
+	// A message, so that it is an event in the protocol mode
+	options.Renderer.Message(fmt.Sprintf("Session recorded to %s\n", *recordFile))
+
+	return nil
+}
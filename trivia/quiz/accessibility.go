@@ -0,0 +1,36 @@
+package quiz
+
+import (
+	"io"
+	"time"
+)
+
+// Name of the accessible renderer for NewRendererByName
+const RendererAccessible string = "accessible"
+
+// Used when the configuration gives no factor
+const defaultTimeLimitFactor float64 = 2
+
+// Makes FormatQuestion announce the number of options and name each one, for
+// screen readers
+func (quiz *Quiz) SetAccessible(accessible bool) {
+	quiz.accessible = accessible
+}
+
+// Linear text for screen readers: no color, symbols or cursor movement,
+// verdicts as the words "Correct" and "Incorrect" and every question
+// announced with its number
+func NewAccessibleRenderer(out io.Writer, localizer *Localizer) Renderer {
+	return &textRenderer{out: out, localizer: localizer, correct: "%s", wrong: "%s", accessible: true}
+}
+
+// Returns the time limit of the accessible mode, the given limit made longer
+// by the configured factor. No limit stays no limit, and a factor below 1,
+// which would make the limit shorter, is replaced by the default.
+func (accessibility AccessibilityObject) TimeLimit(timeLimit time.Duration) time.Duration {
+	factor := accessibility.TimeLimitFactor
+	if factor < 1 {
+		factor = defaultTimeLimitFactor
+	}
+	return time.Duration(float64(timeLimit) * factor)
+}
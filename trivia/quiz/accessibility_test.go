@@ -0,0 +1,72 @@
+package quiz
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestQuiz_FormatQuestion_Accessible(t *testing.T) {
+	quiz := NewQuizWithShuffler(reverseShuffler{})
+	quiz.SetAccessible(true)
+	answerMap := map[string]string{"1": "Red", "2": "Blue"}
+
+	assert.Equal(t, "\nQuestion: Which color has the sky?\n2 options:\nOption 1: Red\nOption 2: Blue\nAnswer: ",
+		quiz.FormatQuestion(translatedQuestion, answerMap))
+}
+
+func TestAccessibleRenderer(t *testing.T) {
+	var out bytes.Buffer
+	renderer := NewAccessibleRenderer(&out, nil)
+
+	renderer.QuestionShown(QuestionEvent{Number: 1, NumberQuestions: 2, Text: "\nQuestion: Which color has the sky?"})
+	renderer.AnswerVerdict(wrongVerdict)
+	renderer.AnswerVerdict(VerdictEvent{Correct: true})
+	renderer.AnswerVerdict(VerdictEvent{RightAnswer: "Blue", TimedOut: true})
+	renderer.AnswersRevealed(RevealEvent{RightAnswer: "Blue", Answers: []VerdictEvent{{Player: "anna", Correct: true}}})
+
+	assert.Equal(t, "Question 1 of 2.\nQuestion: Which color has the sky?\n"+
+		"Incorrect. The correct answer is 'Blue'.\n\n"+
+		"Correct.\n\n"+
+		"Time is up. The correct answer is 'Blue'\n\n"+
+		"The correct answer is 'Blue'\n  anna: Correct\n\n", out.String())
+	assert.NotContains(t, out.String(), "\033")
+}
+
+func TestAccessibilityObject_TimeLimit(t *testing.T) {
+	assert.Equal(t, 40*time.Second, AccessibilityObject{}.TimeLimit(20*time.Second))
+	assert.Equal(t, 30*time.Second, AccessibilityObject{TimeLimitFactor: 1.5}.TimeLimit(20*time.Second))
+	assert.Equal(t, 40*time.Second, AccessibilityObject{TimeLimitFactor: 0.5}.TimeLimit(20*time.Second))
+	assert.Equal(t, time.Duration(0), AccessibilityObject{TimeLimitFactor: 3}.TimeLimit(0))
+}
+
+func TestRunWithOptions_TimeLimit(t *testing.T) {
+	typed := make(chan time.Time)
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion, testQuestion2}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("")
+	// The first answer comes too late and answers the second question
+	quizMock.On("GetUserInput", mock.Anything).WaitUntil(typed).Return("4", nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, "4").Return(true, nil)
+	quizMock.On("FormatResult", 1, 2).Return("Formatted result")
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		close(typed)
+	}()
+
+	var out bytes.Buffer
+	history := NewHistory(t.TempDir())
+	err := RunWithOptions(quizMock, nil, Options{TimeLimit: 200 * time.Millisecond, History: history, Renderer: NewAccessibleRenderer(&out, nil)})
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "Question 1 of 2.\nTime is up. The correct answer is 'Go'\n\nQuestion 2 of 2.\nCorrect.\n")
+	quizMock.AssertNumberOfCalls(t, "GetUserInput", 1)
+	records, _ := history.Records()
+	assert.True(t, records[0].Answers[0].TimedOut)
+	assert.True(t, records[0].Answers[1].Correct)
+}
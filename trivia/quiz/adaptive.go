@@ -0,0 +1,172 @@
+package quiz
+
+import (
+	"fmt"
+	"strconv"
+)
+
+var difficultyLevels = []string{"easy", "medium", "hard"}
+
+const defaultAdaptiveWindow int = 3
+
+// Staircase that moves the difficulty up or down depending on the accuracy
+// of the most recent answers. The window is cleared after every change so
+// that the player gets a few questions at the new level before moving again.
+type adaptiveDifficulty struct {
+	level  int
+	window int
+	recent []bool
+}
+
+func newAdaptiveDifficulty(window int) *adaptiveDifficulty {
+	if window <= 0 {
+		window = defaultAdaptiveWindow
+	}
+	return &adaptiveDifficulty{level: 1, window: window}
+}
+
+func (adaptive *adaptiveDifficulty) Difficulty() string {
+	return difficultyLevels[adaptive.level]
+}
+
+func (adaptive *adaptiveDifficulty) Record(isAnswerCorrect bool) {
+	adaptive.recent = append(adaptive.recent, isAnswerCorrect)
+	if len(adaptive.recent) > adaptive.window {
+		adaptive.recent = adaptive.recent[1:]
+	}
+	if len(adaptive.recent) < adaptive.window {
+		return
+	}
+
+	accuracy := adaptive.accuracy()
+	if accuracy >= 2.0/3.0 && adaptive.level < len(difficultyLevels)-1 {
+		adaptive.level++
+		adaptive.recent = nil
+	} else if accuracy <= 1.0/3.0 && adaptive.level > 0 {
+		adaptive.level--
+		adaptive.recent = nil
+	}
+}
+
+func (adaptive *adaptiveDifficulty) accuracy() float64 {
+	if len(adaptive.recent) == 0 {
+		return 0
+	}
+	correctAnswers := 0
+	for _, isAnswerCorrect := range adaptive.recent {
+		if isAnswerCorrect {
+			correctAnswers++
+		}
+	}
+	return float64(correctAnswers) / float64(len(adaptive.recent))
+}
+
+// Questions fetched per difficulty level, filled on demand
+type questionPools struct {
+	quiz          QuizInterface
+	configuration Configuration
+	pools         map[string][]Question
+	asked         map[string]bool
+}
+
+func newQuestionPools(quiz QuizInterface, configuration Configuration) *questionPools {
+	return &questionPools{
+		quiz:          quiz,
+		configuration: configuration,
+		pools:         map[string][]Question{},
+		asked:         map[string]bool{},
+	}
+}
+
+// Returns the next question that has not been asked yet for the given
+// difficulty, fetching the pool for that difficulty the first time
+func (pools *questionPools) Next(difficulty string) (Question, bool, error) {
+	pool, fetched := pools.pools[difficulty]
+	if !fetched {
+		poolConfig := pools.configuration
+		poolConfig.Trivia.Difficulty = difficulty
+		questions, err := pools.quiz.GetQuestions(poolConfig)
+		if err != nil {
+			return Question{}, false, err
+		}
+		for _, question := range questions {
+			// Local question files are not filtered by difficulty, their
+			// untagged questions are in every pool and stay untagged
+			if question.Difficulty == "" || question.Difficulty == difficulty {
+				pool = append(pool, question)
+			}
+		}
+	}
+
+	for len(pool) > 0 {
+		question := pool[0]
+		pool = pool[1:]
+		if !pools.asked[question.Question] {
+			pools.pools[difficulty] = pool
+			pools.asked[question.Question] = true
+			return question, true, nil
+		}
+	}
+	pools.pools[difficulty] = pool
+
+	return Question{}, false, nil
+}
+
+// Plays a game where the difficulty of the next question follows the
+// player's recent accuracy
+func (game *game) playAdaptive(configuration Configuration) error {
+	numberQuestions, err := strconv.Atoi(configuration.Trivia.Amount)
+	if err != nil {
+		return fmt.Errorf("Invalid amount '%s': %s", configuration.Trivia.Amount, err.Error())
+	}
+
+	adaptive := newAdaptiveDifficulty(configuration.Adaptive.Window)
+	pools := newQuestionPools(game.quiz, configuration)
+	correctAnswers := 0
+	askedQuestions := 0
+
+	for askedQuestions < numberQuestions {
+		question, difficulty, found, err := nextAdaptiveQuestion(pools, adaptive.level)
+		if err != nil {
+			return err
+		}
+		if !found {
+			game.renderer.Message(game.localizer.Text("no_more_questions") + "\n")
+			break
+		}
+
+		game.renderer.Message(fmt.Sprintf("[%s] ", difficulty))
+		isAnswerCorrect, err := game.askQuestion(question, askedQuestions+1, numberQuestions)
+		if err != nil {
+			return err
+		}
+		if isAnswerCorrect {
+			correctAnswers++
+		}
+		askedQuestions++
+		adaptive.Record(isAnswerCorrect)
+	}
+
+	game.gameOver(game.quiz.FormatResult(correctAnswers, askedQuestions), nil)
+
+	return nil
+}
+
+// Picks a question at the wanted level, falling back to the closest level
+// that still has questions left, and returns the level it was picked at
+func nextAdaptiveQuestion(pools *questionPools, level int) (Question, string, bool, error) {
+	for distance := 0; distance < len(difficultyLevels); distance++ {
+		for _, candidate := range []int{level - distance, level + distance} {
+			if candidate < 0 || candidate >= len(difficultyLevels) {
+				continue
+			}
+			difficulty := difficultyLevels[candidate]
+			question, found, err := pools.Next(difficulty)
+			if err != nil || found {
+				return question, difficulty, found, err
+			}
+		}
+	}
+
+	return Question{}, "", false, nil
+}
@@ -0,0 +1,119 @@
+package quiz
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAdaptiveDifficultyStartsAtMedium(t *testing.T) {
+	adaptive := newAdaptiveDifficulty(0)
+	assert.Equal(t, "medium", adaptive.Difficulty())
+	assert.Equal(t, defaultAdaptiveWindow, adaptive.window)
+}
+
+func TestAdaptiveDifficultyMovesUp(t *testing.T) {
+	adaptive := newAdaptiveDifficulty(3)
+	adaptive.Record(true)
+	adaptive.Record(false)
+	assert.Equal(t, "medium", adaptive.Difficulty())
+	adaptive.Record(true)
+	assert.Equal(t, "hard", adaptive.Difficulty())
+
+	adaptive.Record(true)
+	adaptive.Record(true)
+	adaptive.Record(true)
+	assert.Equal(t, "hard", adaptive.Difficulty())
+}
+
+func TestAdaptiveDifficultyMovesDown(t *testing.T) {
+	adaptive := newAdaptiveDifficulty(2)
+	adaptive.Record(false)
+	adaptive.Record(false)
+	assert.Equal(t, "easy", adaptive.Difficulty())
+
+	adaptive.Record(false)
+	adaptive.Record(false)
+	assert.Equal(t, "easy", adaptive.Difficulty())
+}
+
+func TestQuestionPoolsNext(t *testing.T) {
+	easyQuestion := Question{Question: "Easy?", Difficulty: "easy"}
+	hardQuestion := Question{Question: "Hard?", Difficulty: "hard"}
+
+	quizMock := &QuizMock{}
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{easyQuestion, hardQuestion, testQuestion}, nil)
+
+	pools := newQuestionPools(quizMock, testConfiguration)
+
+	question, found, err := pools.Next("easy")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, easyQuestion, question)
+
+	question, found, _ = pools.Next("easy")
+	assert.True(t, found)
+	assert.Equal(t, testQuestion, question)
+	assert.Empty(t, question.Difficulty)
+
+	_, found, _ = pools.Next("easy")
+	assert.False(t, found)
+
+	question, found, _ = pools.Next("hard")
+	assert.True(t, found)
+	assert.Equal(t, hardQuestion, question)
+
+	quizMock.AssertNumberOfCalls(t, "GetQuestions", 2)
+}
+
+func TestRun_Adaptive(t *testing.T) {
+	configuration := Configuration{
+		Trivia:   TriviaObject{Amount: "3"},
+		Adaptive: AdaptiveObject{Enabled: true, Window: 1},
+	}
+	mediumConfig := configuration
+	mediumConfig.Trivia.Difficulty = "medium"
+	hardConfig := configuration
+	hardConfig.Trivia.Difficulty = "hard"
+	easyConfig := configuration
+	easyConfig.Trivia.Difficulty = "easy"
+
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(configuration, nil)
+	quizMock.On("GetQuestions", mediumConfig).Return([]Question{{Question: "M", Difficulty: "medium"}}, nil)
+	quizMock.On("GetQuestions", hardConfig).Return([]Question{{Question: "H", Difficulty: "hard"}}, nil)
+	quizMock.On("GetQuestions", easyConfig).Return([]Question{{Question: "E", Difficulty: "easy"}}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	quizMock.On("FormatResult", mock.Anything, mock.Anything).Return("Formatted result")
+
+	var stdin bytes.Buffer
+	err := Run(quizMock, &stdin)
+
+	assert.NoError(t, err)
+	quizMock.AssertCalled(t, "FormatQuestion", Question{Question: "M", Difficulty: "medium"}, testAnswerMap)
+	quizMock.AssertCalled(t, "FormatQuestion", Question{Question: "H", Difficulty: "hard"}, testAnswerMap)
+	quizMock.AssertCalled(t, "FormatQuestion", Question{Question: "E", Difficulty: "easy"}, testAnswerMap)
+	quizMock.AssertCalled(t, "FormatResult", 1, 3)
+}
+
+func TestRun_AdaptiveInvalidAmount(t *testing.T) {
+	configuration := Configuration{
+		Trivia:   TriviaObject{Amount: "many"},
+		Adaptive: AdaptiveObject{Enabled: true},
+	}
+
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(configuration, nil)
+
+	var stdin bytes.Buffer
+	err := Run(quizMock, &stdin)
+
+	assert.Error(t, err)
+	quizMock.AssertNotCalled(t, "GetQuestions", mock.Anything)
+}
@@ -0,0 +1,148 @@
+package quiz
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Types of the blocks of a question
+const ContentText string = "text"
+const ContentCode string = "code"
+const ContentImage string = "image"
+
+const codeFence string = "```"
+const codeIndent string = "    "
+
+// An image on a line of its own, as in Markdown: ![description](url)
+var imageLine = regexp.MustCompile(`^!\[([^\]]*)\]\((\S+)\)$`)
+
+// A part of a question: a paragraph of text, a code snippet or an image
+type ContentBlock struct {
+	Type string `json:"type"`
+	// The text, the code, or the description of the image
+	Text string `json:"text,omitempty"`
+	// Language of the code, e.g. go
+	Language string `json:"language,omitempty"`
+	// Address of the image
+	URL string `json:"url,omitempty"`
+}
+
+// Returns the question as blocks: the question text, where ``` fences code
+// and ![description](url) on a line of its own is an image, followed by the
+// content given in the deck
+func (question Question) Blocks() []ContentBlock {
+	var blocks []ContentBlock
+	for _, block := range parseContent(question.Question) {
+		if block.Type == ContentText && strings.TrimSpace(block.Text) == "" {
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+	return append(blocks, question.Content...)
+}
+
+// Returns the blocks of the question when it is more than a single paragraph
+// of text, for clients that show code and images themselves
+func (question Question) RichContent() []ContentBlock {
+	blocks := question.Blocks()
+	if len(blocks) == 1 && blocks[0].Type == ContentText {
+		return nil
+	}
+	return blocks
+}
+
+// Returns the blocks as text for the terminal: text as it is, code indented,
+// and highlighted when color is true, and images as links
+func RenderBlocks(blocks []ContentBlock, color bool, localizer *Localizer) string {
+	var parts []string
+	for _, block := range blocks {
+		switch block.Type {
+		case ContentCode:
+			code := block.Text
+			if color {
+				code = HighlightCode(code, block.Language)
+			}
+			lines := strings.Split(code, "\n")
+			for index, line := range lines {
+				lines[index] = codeIndent + line
+			}
+			parts = append(parts, strings.Join(lines, "\n"))
+		case ContentImage:
+			parts = append(parts, localizer.Text("image", block.Text, block.URL))
+		default:
+			parts = append(parts, block.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Splits text into blocks, keeping the text between code and images exactly
+// as it is so that plain text comes back unchanged
+func parseContent(text string) []ContentBlock {
+	var blocks []ContentBlock
+	var lines []string
+	var code *ContentBlock
+	var codeLines []string
+
+	flushText := func() {
+		if lines != nil {
+			blocks = append(blocks, ContentBlock{Type: ContentText, Text: strings.Join(lines, "\n")})
+			lines = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if code != nil {
+			if trimmed == codeFence {
+				code.Text = strings.Join(codeLines, "\n")
+				blocks = append(blocks, *code)
+				code = nil
+				continue
+			}
+			codeLines = append(codeLines, line)
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, codeFence) {
+			flushText()
+			code = &ContentBlock{Type: ContentCode, Language: strings.TrimSpace(strings.TrimPrefix(trimmed, codeFence))}
+			codeLines = []string{}
+			continue
+		}
+		if match := imageLine.FindStringSubmatch(trimmed); match != nil {
+			flushText()
+			blocks = append(blocks, ContentBlock{Type: ContentImage, Text: match[1], URL: match[2]})
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	// Code without a closing fence lasts to the end
+	if code != nil {
+		code.Text = strings.Join(codeLines, "\n")
+		blocks = append(blocks, *code)
+	}
+	flushText()
+
+	return blocks
+}
+
+func validateContent(blocks []ContentBlock) error {
+	for _, block := range blocks {
+		switch block.Type {
+		case ContentText, ContentCode:
+			if strings.TrimSpace(block.Text) == "" {
+				return fmt.Errorf("A %s block must not be empty", block.Type)
+			}
+		case ContentImage:
+			if strings.TrimSpace(block.Text) == "" || block.URL == "" {
+				return fmt.Errorf("An image needs a description and a URL")
+			}
+		default:
+			return fmt.Errorf("Unknown content type '%s', use %s, %s or %s", block.Type, ContentText, ContentCode, ContentImage)
+		}
+	}
+	return nil
+}
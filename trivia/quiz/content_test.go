@@ -0,0 +1,60 @@
+package quiz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var codeQuestion = Question{
+	Question:     "What does this program print?\n```go\nfmt.Println(len(\"héllo\"))\n```",
+	RightAnswer:  "6",
+	WrongAnswers: [3]string{"5", "4", "An error"},
+	Content:      []ContentBlock{{Type: ContentImage, Text: "The Go gopher", URL: "https://go.dev/images/gopher.png"}},
+}
+
+func TestQuestion_Blocks(t *testing.T) {
+	assert.Equal(t, []ContentBlock{
+		{Type: ContentText, Text: "What does this program print?"},
+		{Type: ContentCode, Language: "go", Text: "fmt.Println(len(\"héllo\"))"},
+		{Type: ContentImage, Text: "The Go gopher", URL: "https://go.dev/images/gopher.png"},
+	}, codeQuestion.Blocks())
+	assert.Equal(t, []ContentBlock{{Type: ContentText, Text: testQuestion.Question}}, testQuestion.Blocks())
+	assert.Nil(t, testQuestion.RichContent())
+	assert.Len(t, codeQuestion.RichContent(), 3)
+}
+
+func TestRenderBlocks(t *testing.T) {
+	assert.Equal(t, "What does this program print?\n    fmt.Println(len(\"héllo\"))\n"+
+		"Image: The Go gopher (https://go.dev/images/gopher.png)", RenderBlocks(codeQuestion.Blocks(), false, nil))
+	assert.Contains(t, RenderBlocks(codeQuestion.Blocks(), true, nil), "    fmt.Println(len("+colorGreen)
+	assert.Equal(t, "Plain ```text``` ![not](an image)", RenderBlocks([]ContentBlock{{Type: ContentText, Text: "Plain ```text``` ![not](an image)"}}, true, nil))
+}
+
+func TestQuiz_FormatQuestion_Content(t *testing.T) {
+	quiz := NewQuizWithShuffler(reverseShuffler{})
+	answerMap := map[string]string{"1": "6"}
+
+	assert.Equal(t, "\nQuestion: What does this program print?\n    fmt.Println(len(\"héllo\"))\n"+
+		"Image: The Go gopher (https://go.dev/images/gopher.png)\n1: 6\nAnswer: ", quiz.FormatQuestion(codeQuestion, answerMap))
+
+	codeOnly := Question{Question: "```go\nx := 1\n```"}
+	assert.Equal(t, "\nQuestion:\n    x := 1\n1: 6\nAnswer: ", quiz.FormatQuestion(codeOnly, answerMap))
+
+	quiz.SetColor(true)
+	assert.Contains(t, quiz.FormatQuestion(codeOnly, answerMap), "    x := "+colorCyan+"1"+colorReset)
+}
+
+func TestValidateQuestion_Content(t *testing.T) {
+	assert.NoError(t, ValidateQuestion(codeQuestion))
+
+	broken := codeQuestion
+	broken.Content = []ContentBlock{{Type: "video", URL: "https://example.com/go.mp4"}}
+	assert.EqualError(t, ValidateQuestion(broken), "Unknown content type 'video', use text, code or image")
+
+	broken.Content = []ContentBlock{{Type: ContentImage, URL: "https://go.dev/images/gopher.png"}}
+	assert.EqualError(t, ValidateQuestion(broken), "An image needs a description and a URL")
+
+	broken.Content = []ContentBlock{{Type: ContentCode, Language: "go"}}
+	assert.EqualError(t, ValidateQuestion(broken), "A code block must not be empty")
+}
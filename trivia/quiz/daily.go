@@ -0,0 +1,223 @@
+package quiz
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const defaultDailyAmount int = 5
+
+// The questions of the day, the same for every player
+type DailyChallenge struct {
+	Date      string
+	Seed      int64
+	Questions []Question
+}
+
+// A player's attempt at the challenge of a day. An attempt is stored when
+// the game starts so that it cannot be tried again after giving up.
+type DailyResult struct {
+	Player          string        `json:"player"`
+	GameID          string        `json:"game_id,omitempty"`
+	Finished        bool          `json:"finished"`
+	CorrectAnswers  int           `json:"correct_answers"`
+	NumberQuestions int           `json:"number_questions"`
+	Duration        time.Duration `json:"duration"`
+}
+
+// Attempts at the daily challenges stored as JSON, by date and player
+type DailyRecord struct {
+	File string
+}
+
+func NewDailyRecord(dataDir string) *DailyRecord {
+	return &DailyRecord{File: filepath.Join(dataDir, "daily.json")}
+}
+
+// Returns the seed of the day, the same on every computer
+func DailySeed(date string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(date))
+	return int64(hash.Sum64())
+}
+
+// Picks the questions of the day from the questions of the local decks
+func NewDailyChallenge(questions []Question, date time.Time, amount int) (DailyChallenge, error) {
+	if len(questions) == 0 {
+		return DailyChallenge{}, fmt.Errorf("There are no questions for the daily challenge")
+	}
+	if amount <= 0 {
+		amount = defaultDailyAmount
+	}
+
+	challenge := DailyChallenge{Date: date.Format(dateLayout)}
+	challenge.Seed = DailySeed(challenge.Date)
+
+	shuffled := append([]Question{}, questions...)
+	random := rand.New(rand.NewSource(challenge.Seed))
+	random.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	if len(shuffled) > amount {
+		shuffled = shuffled[:amount]
+	}
+	challenge.Questions = shuffled
+
+	return challenge, nil
+}
+
+// Plays the challenge for the player of the options. The quiz should shuffle
+// with the seed of the challenge so that every player gets the same answer
+// order. Returns ErrQuit when the player quits, the challenge is not finished
+// then.
+func PlayDaily(quiz QuizInterface, stdin io.Reader, challenge DailyChallenge, options Options) (GameRecord, error) {
+	currentGame := newGameWithOptions(quiz, stdin, options)
+	currentGame.record = GameRecord{
+		Player:  options.Player,
+		Started: time.Now(),
+		Mode:    "daily",
+		Seed:    challenge.Seed,
+	}
+	currentGame.renderer.Message(currentGame.localizer.Text("daily_challenge", challenge.Date) + "\n")
+
+	correctAnswers, err := currentGame.askQuestions(challenge.Questions)
+	if err == ErrQuit {
+		// A challenge cannot be resumed, it is over with the score so far
+		quitErr := currentGame.quit(options)
+		if quitErr != nil {
+			return GameRecord{}, quitErr
+		}
+	}
+	if err != nil {
+		return GameRecord{}, err
+	}
+	currentGame.gameOver(quiz.FormatResult(correctAnswers, len(challenge.Questions)), nil)
+
+	return currentGame.finish(), nil
+}
+
+func (daily *DailyRecord) read() (map[string][]DailyResult, error) {
+	days := map[string][]DailyResult{}
+
+	file, err := ioutil.ReadFile(daily.File)
+	if os.IsNotExist(err) {
+		return days, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(file, &days)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse %s: %s", daily.File, err.Error())
+	}
+
+	return days, nil
+}
+
+func (daily *DailyRecord) write(days map[string][]DailyResult) error {
+	data, err := json.MarshalIndent(days, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(daily.File, data)
+}
+
+// Returns the attempts at the challenge of the date
+func (daily *DailyRecord) Results(date string) ([]DailyResult, error) {
+	days, err := daily.read()
+	if err != nil {
+		return nil, err
+	}
+	return days[date], nil
+}
+
+// Stores that the player has started the challenge of the date. Fails when
+// the player has already tried it.
+func (daily *DailyRecord) Start(date string, player string) error {
+	if strings.TrimSpace(player) == "" {
+		return fmt.Errorf("Player name must not be empty")
+	}
+
+	days, err := daily.read()
+	if err != nil {
+		return err
+	}
+	for _, result := range days[date] {
+		if result.Player == player {
+			return fmt.Errorf("%s has already played the daily challenge of %s", player, date)
+		}
+	}
+
+	days[date] = append(days[date], DailyResult{Player: player})
+	return daily.write(days)
+}
+
+// Stores the result of the player's attempt at the challenge of the date
+func (daily *DailyRecord) Finish(date string, record GameRecord) error {
+	days, err := daily.read()
+	if err != nil {
+		return err
+	}
+
+	for index, result := range days[date] {
+		if result.Player == record.Player {
+			days[date][index] = DailyResult{
+				Player:          record.Player,
+				GameID:          record.ID,
+				Finished:        true,
+				CorrectAnswers:  record.CorrectAnswers,
+				NumberQuestions: record.NumberQuestions,
+				Duration:        record.Duration,
+			}
+			return daily.write(days)
+		}
+	}
+
+	return fmt.Errorf("%s has not started the daily challenge of %s", record.Player, date)
+}
+
+// Ranks the finished attempts of a day
+func DailyLeaderboard(results []DailyResult) []PlayerScore {
+	var scores []PlayerScore
+	for _, result := range results {
+		if !result.Finished {
+			continue
+		}
+		scores = append(scores, PlayerScore{
+			Name:            result.Player,
+			CorrectAnswers:  result.CorrectAnswers,
+			NumberQuestions: result.NumberQuestions,
+			Duration:        result.Duration,
+		})
+	}
+	rankPlayerScores(scores)
+
+	return scores
+}
+
+func FormatDailyLeaderboard(date string, results []DailyResult, localizer *Localizer) string {
+	scores := DailyLeaderboard(results)
+	if len(scores) == 0 {
+		return localizer.Text("daily_none", date) + "\n"
+	}
+
+	var builder strings.Builder
+	builder.WriteString(localizer.Text("daily_challenge", date) + "\n")
+	for _, score := range scores {
+		builder.WriteString(localizer.Plural("ranking_entry", score.NumberQuestions,
+			score.Rank, score.Name, score.CorrectAnswers, score.NumberQuestions, score.Duration.Round(time.Second)) + "\n")
+	}
+	unfinished := len(results) - len(scores)
+	if unfinished > 0 {
+		builder.WriteString(localizer.Plural("daily_unfinished", unfinished, unfinished) + "\n")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,100 @@
+package quiz
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var dailyQuestions = []Question{
+	{Question: "Q1"}, {Question: "Q2"}, {Question: "Q3"}, {Question: "Q4"}, {Question: "Q5"}, {Question: "Q6"},
+}
+
+func TestNewDailyChallenge(t *testing.T) {
+	day := time.Date(2021, 3, 8, 9, 0, 0, 0, time.Local)
+
+	challenge, err := NewDailyChallenge(dailyQuestions, day, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "2021-03-08", challenge.Date)
+	assert.Equal(t, DailySeed("2021-03-08"), challenge.Seed)
+	assert.Len(t, challenge.Questions, 3)
+
+	// Later the same day gives the same challenge, the next day another one
+	again, _ := NewDailyChallenge(dailyQuestions, day.Add(12*time.Hour), 3)
+	assert.Equal(t, challenge, again)
+	tomorrow, _ := NewDailyChallenge(dailyQuestions, day.AddDate(0, 0, 1), 3)
+	assert.NotEqual(t, challenge.Seed, tomorrow.Seed)
+
+	assert.Equal(t, "Q1", dailyQuestions[0].Question, "the decks are not reordered")
+
+	all, _ := NewDailyChallenge(dailyQuestions[:2], day, 0)
+	assert.Len(t, all.Questions, 2)
+	_, err = NewDailyChallenge(nil, day, 3)
+	assert.EqualError(t, err, "There are no questions for the daily challenge")
+}
+
+func TestDailyRecord(t *testing.T) {
+	daily := NewDailyRecord(t.TempDir())
+
+	results, err := daily.Results("2021-03-08")
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	assert.NoError(t, daily.Start("2021-03-08", "anna"))
+	assert.EqualError(t, daily.Start("2021-03-08", "anna"), "anna has already played the daily challenge of 2021-03-08")
+	assert.NoError(t, daily.Start("2021-03-09", "anna"))
+	assert.NoError(t, daily.Start("2021-03-08", "bert"))
+	assert.Error(t, daily.Start("2021-03-08", ""))
+
+	record := GameRecord{ID: "abc", Player: "anna", CorrectAnswers: 2, NumberQuestions: 3, Duration: time.Minute}
+	assert.NoError(t, daily.Finish("2021-03-08", record))
+	record.Player = "cecilia"
+	assert.EqualError(t, daily.Finish("2021-03-08", record), "cecilia has not started the daily challenge of 2021-03-08")
+
+	results, _ = daily.Results("2021-03-08")
+	assert.Equal(t, []DailyResult{
+		{Player: "anna", GameID: "abc", Finished: true, CorrectAnswers: 2, NumberQuestions: 3, Duration: time.Minute},
+		{Player: "bert"},
+	}, results)
+}
+
+func TestFormatDailyLeaderboard(t *testing.T) {
+	assert.Equal(t, "Nobody has finished the daily challenge of 2021-03-08 yet.\n",
+		FormatDailyLeaderboard("2021-03-08", []DailyResult{{Player: "bert"}}, nil))
+
+	results := []DailyResult{
+		{Player: "anna", Finished: true, CorrectAnswers: 2, NumberQuestions: 3, Duration: time.Minute},
+		{Player: "bert"},
+		{Player: "cecilia", Finished: true, CorrectAnswers: 2, NumberQuestions: 3, Duration: 30 * time.Second},
+	}
+	expected := "Daily challenge 2021-03-08\n" +
+		"  1. cecilia: 2 of 3 correct answers (30s)\n" +
+		"  2. anna: 2 of 3 correct answers (1m0s)\n" +
+		"1 more started but did not finish.\n"
+	assert.Equal(t, expected, FormatDailyLeaderboard("2021-03-08", results, nil))
+}
+
+func TestPlayDaily(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil)
+	quizMock.On("Verify", testQuestion, mock.Anything, mock.Anything).Return(true, nil)
+	quizMock.On("Verify", testQuestion2, mock.Anything, mock.Anything).Return(false, nil)
+	quizMock.On("FormatResult", 1, 2).Return("Formatted result")
+
+	challenge := DailyChallenge{Date: "2021-03-08", Seed: 42, Questions: []Question{testQuestion, testQuestion2}}
+	var stdin bytes.Buffer
+	record, err := PlayDaily(quizMock, &stdin, challenge, Options{Player: "anna"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "daily", record.Mode)
+	assert.Equal(t, int64(42), record.Seed)
+	assert.Equal(t, "anna", record.Player)
+	assert.Equal(t, 1, record.CorrectAnswers)
+	assert.Equal(t, 2, record.NumberQuestions)
+	quizMock.AssertCalled(t, "FormatResult", 1, 2)
+}
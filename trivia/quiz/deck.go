@@ -0,0 +1,61 @@
+package quiz
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Writes the questions as a local deck that ReadDeck can read back
+func WriteDeck(jsonFile string, questions []Question) error {
+	if questions == nil {
+		questions = []Question{}
+	}
+	data, err := json.MarshalIndent(questions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFile(jsonFile, append(data, '\n'))
+}
+
+// Checks that the question has a text and four different, non-empty answers
+func ValidateQuestion(question Question) error {
+	if strings.TrimSpace(question.Question) == "" {
+		return fmt.Errorf("The question text must not be empty")
+	}
+
+	answers := append([]string{question.RightAnswer}, question.WrongAnswers[:]...)
+	seen := map[string]bool{}
+	for _, answer := range answers {
+		if strings.TrimSpace(answer) == "" {
+			return fmt.Errorf("The question needs a correct answer and three incorrect answers")
+		}
+		if seen[answer] {
+			return fmt.Errorf("The answer '%s' is given more than once", answer)
+		}
+		seen[answer] = true
+	}
+
+	err := validateContent(question.Blocks())
+	if err != nil {
+		return err
+	}
+
+	if question.Source != "" {
+		source, err := url.Parse(question.Source)
+		if err != nil || (source.Scheme != "http" && source.Scheme != "https") || source.Host == "" {
+			return fmt.Errorf("The source '%s' is not an http or https URL", question.Source)
+		}
+	}
+
+	for language := range question.Translations {
+		err := ValidateQuestion(question.Translate(language))
+		if err != nil {
+			return fmt.Errorf("Translation '%s': %s", language, err.Error())
+		}
+	}
+
+	return nil
+}
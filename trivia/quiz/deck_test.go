@@ -0,0 +1,44 @@
+package quiz
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteAndReadDeck(t *testing.T) {
+	deckFile := filepath.Join(t.TempDir(), "decks", "go.json")
+
+	assert.NoError(t, WriteDeck(deckFile, []Question{testQuestion, testQuestion2}))
+	questions, err := ReadDeck(deckFile)
+	assert.NoError(t, err)
+	assert.Equal(t, []Question{testQuestion, testQuestion2}, questions)
+
+	assert.NoError(t, WriteDeck(deckFile, nil))
+	data, _ := ioutil.ReadFile(deckFile)
+	assert.Equal(t, "[]\n", string(data))
+}
+
+func TestValidateQuestion(t *testing.T) {
+	assert.NoError(t, ValidateQuestion(testQuestion))
+
+	assert.EqualError(t, ValidateQuestion(Question{RightAnswer: "Go"}), "The question text must not be empty")
+
+	missingAnswer := testQuestion
+	missingAnswer.WrongAnswers[2] = " "
+	assert.EqualError(t, ValidateQuestion(missingAnswer), "The question needs a correct answer and three incorrect answers")
+
+	duplicateAnswer := testQuestion
+	duplicateAnswer.WrongAnswers[0] = "Go"
+	assert.EqualError(t, ValidateQuestion(duplicateAnswer), "The answer 'Go' is given more than once")
+
+	sourced := testQuestion
+	sourced.Source = "https://go.dev/doc/"
+	assert.NoError(t, ValidateQuestion(sourced))
+	for _, source := range []string{"go.dev", "ftp://go.dev", "https://"} {
+		sourced.Source = source
+		assert.EqualError(t, ValidateQuestion(sourced), "The source '"+source+"' is not an http or https URL")
+	}
+}
@@ -0,0 +1,42 @@
+package quiz
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultDataDir string = "data"
+
+// Returns the directory where game data such as review state is kept
+func DataDir(configuration Configuration) string {
+	if configuration.DataDir == "" {
+		return defaultDataDir
+	}
+	return configuration.DataDir
+}
+
+// Replaces characters that are not safe in file names
+func SafeFileName(name string) string {
+	safeName := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+
+	if safeName == "" {
+		return "_"
+	}
+	return safeName
+}
+
+// Writes the file, creating its directory when needed
+func writeFile(path string, data []byte) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
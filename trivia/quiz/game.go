@@ -1,60 +1,439 @@
 package quiz
 
 import (
-	"fmt"
+	"errors"
 	"io"
+	"os"
+	"strings"
+	"time"
 )
 
-// https://golangbyexample.com/print-output-text-color-console/
-const colorReset string = "\033[0m"
-const colorRed string = "\033[31m"
-const colorGreen string = "\033[32m"
+const defaultConfigFile string = "resources/config.yaml"
 
-const randomizeAnswers bool = true
+type Options struct {
+	ConfigFile string
+	Player     string
+	// Two or more players make a hot-seat game
+	Players []string
+	HotSeat string
+	// Two or more teams of hot-seat players make a team game
+	Teams     []Team
+	Consensus string
+	History   *History
+	// Seed the quiz shuffles with, printed at the end so that the game can be
+	// played again. Zero means the seed is unknown.
+	Seed int64
+	// Presents the game, by default the renderer suiting stdout
+	Renderer Renderer
+	// Language of the text the game prints itself, English when nil
+	Localizer *Localizer
+	// File a classic game is saved to when the player quits it, not saved
+	// when empty
+	SaveFile string
+	// Quits the game like the quit command when a signal arrives, e.g. on
+	// Ctrl-C
+	Interrupt <-chan os.Signal
+	// Time to answer each question, an answer not given in time is wrong.
+	// Zero means no limit.
+	TimeLimit time.Duration
+	// Label scheme of the answer options, kept with a saved game
+	Labels string
+}
+
+// State of a game in progress
+type game struct {
+	quiz      QuizInterface
+	stdin     io.Reader
+	renderer  Renderer
+	localizer *Localizer
+	round     string
+	record    GameRecord
+	interrupt <-chan os.Signal
+	timeLimit time.Duration
+	// Input still being read when the time was up, for the next question
+	pending chan userInput
+	// Questions not answered yet, for saving the game when it is quit
+	remaining []Question
+}
+
+type userInput struct {
+	text string
+	err  error
+}
+
+// Returned by readInput, or by the GetUserInput of a quiz that keeps the time
+// itself, when the time to answer is up
+var ErrTimeUp = errors.New("The time is up")
+
+// Implemented by quizzes that want to know when the time to answer was up,
+// such as the Recorder
+type timeUpListener interface {
+	TimeUp()
+}
+
+// Implemented by quizzes that can highlight the code of their questions, such
+// as Quiz, so that the questions match the renderer
+type colorSetter interface {
+	SetColor(color bool)
+}
+
+// Implemented by quizzes that can tell the shuffles they made, such as Quiz,
+// so that a saved game is resumed with the same answer orders
+type drawCounter interface {
+	Draws() []int
+}
+
+func newGame(quiz QuizInterface, stdin io.Reader) *game {
+	return &game{quiz: quiz, stdin: stdin, renderer: NewRenderer(os.Stdout, nil)}
+}
+
+// Creates a game presented by the renderer and in the language of the options
+func newGameWithOptions(quiz QuizInterface, stdin io.Reader, options Options) *game {
+	currentGame := &game{quiz: quiz, stdin: stdin, localizer: options.Localizer, renderer: options.Renderer,
+		interrupt: options.Interrupt, timeLimit: options.TimeLimit}
+	if currentGame.renderer == nil {
+		currentGame.renderer = NewRenderer(os.Stdout, options.Localizer)
+	}
+	if setter, ok := quiz.(colorSetter); ok {
+		setter.SetColor(UsesColor(currentGame.renderer))
+	}
+	return currentGame
+}
 
 func Run(quiz QuizInterface, stdin io.Reader) error {
-	configuration, err := quiz.ReadConfigurationFromYAML("resources/config.yaml")
+	return RunWithOptions(quiz, stdin, Options{})
+}
+
+// Plays a game and, when a history is given, records it once it is finished
+func RunWithOptions(quiz QuizInterface, stdin io.Reader, options Options) error {
+	configFile := options.ConfigFile
+	if configFile == "" {
+		configFile = defaultConfigFile
+	}
+
+	if len(options.Teams) > 0 {
+		err := validateTeams(options.Teams, options.Consensus)
+		if err != nil {
+			return err
+		}
+		options.Players = nil
+	} else if len(options.Players) > 0 {
+		err := validatePlayers(options.Players, options.HotSeat)
+		if err != nil {
+			return err
+		}
+	}
+
+	configuration, err := quiz.ReadConfigurationFromYAML(configFile)
 	if err != nil {
 		return err
 	}
 
-	questions, err := quiz.GetQuestions(configuration)
+	currentGame := newGameWithOptions(quiz, stdin, options)
+	currentGame.record = GameRecord{
+		Player:        options.Player,
+		Started:       time.Now(),
+		Seed:          options.Seed,
+		Configuration: configuration,
+	}
+
+	if len(options.Teams) > 0 {
+		currentGame.record.Mode = "team"
+		err = currentGame.playTeams(configuration, options.Teams, options.Consensus)
+	} else if len(options.Players) > 0 {
+		currentGame.record.Mode = "hotseat"
+		err = currentGame.playHotSeat(configuration, options.Players, options.HotSeat)
+	} else if len(configuration.Rounds) > 0 {
+		currentGame.record.Mode = "rounds"
+		err = currentGame.playRounds(configuration)
+	} else if configuration.Adaptive.Enabled {
+		currentGame.record.Mode = "adaptive"
+		err = currentGame.playAdaptive(configuration)
+	} else {
+		currentGame.record.Mode = "classic"
+		err = currentGame.playClassic(configuration)
+	}
+	if err == ErrQuit {
+		return currentGame.quit(options)
+	}
+	if err != nil {
+		return err
+	}
+	if options.Seed != 0 {
+		currentGame.renderer.Message(currentGame.localizer.Text("seed", options.Seed, options.Seed) + "\n")
+	}
+
+	if options.History == nil {
+		return nil
+	}
+	record := currentGame.finish()
+	players := options.Players
+	if len(options.Teams) > 0 {
+		players = teamPlayers(options.Teams)
+	}
+	if len(players) == 0 {
+		return options.History.Append(record)
+	}
+	for _, playerRecord := range splitRecord(record, players) {
+		err = options.History.Append(playerRecord)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (game *game) playClassic(configuration Configuration) error {
+	questions, err := game.quiz.GetQuestions(configuration)
 	if err != nil {
 		return err
 	}
 
+	correctAnswers, err := game.askQuestions(questions)
+	if err != nil {
+		return err
+	}
+
+	game.gameOver(game.quiz.FormatResult(correctAnswers, len(questions)), nil)
+
+	return nil
+}
+
+// Tells the renderer the game is over, with the result as formatted by the
+// quiz and the ranking when several players or teams played, or else the
+// questions the player missed
+func (game *game) gameOver(text string, scores []PlayerScore) {
+	event := GameOverEvent{
+		CorrectAnswers:  game.record.CorrectAnswers,
+		NumberQuestions: game.record.NumberQuestions,
+		Scores:          scores,
+		Text:            text,
+	}
+	if len(scores) == 0 {
+		for _, answer := range game.record.Answers {
+			if !answer.Correct {
+				event.Review = append(event.Review, verdictEvent(answer))
+			}
+		}
+	}
+	game.renderer.GameOver(event)
+}
+
+// Completes the record of the game
+func (game *game) finish() GameRecord {
+	game.record.ID = newRecordID(game.record.Started)
+	game.record.Duration = time.Since(game.record.Started)
+
+	return game.record
+}
+
+// Asks each question in turn and returns the number of correct answers
+func (game *game) askQuestions(questions []Question) (int, error) {
+	return game.askRemaining(questions, 0, len(questions))
+}
+
+// Asks the questions left of a game where some were answered already,
+// numbering them on from there, and returns the number of correct answers
+func (game *game) askRemaining(questions []Question, answered int, numberQuestions int) (int, error) {
 	correctAnswers := 0
+	before := len(game.record.Answers)
 
 	for index, question := range questions {
-		answerMap := quiz.GetAnswerMap(question, randomizeAnswers)
+		isAnswerCorrect, err := game.askQuestion(question, answered+index+1, numberQuestions)
+		if err != nil {
+			game.remaining = questions[len(game.record.Answers)-before:]
+			return correctAnswers, err
+		}
+		if isAnswerCorrect {
+			correctAnswers++
+		}
+	}
+	game.remaining = nil
+
+	return correctAnswers, nil
+}
 
-		fmt.Printf("%d/%d", index+1, len(questions))
-		fmt.Println(quiz.FormatQuestion(question, answerMap))
+// Asks a single question until a valid answer is given
+func (game *game) askQuestion(question Question, number int, numberQuestions int) (bool, error) {
+	answerMap := game.showQuestion(question, number, numberQuestions)
 
-		for {
-			userInput, inputError := quiz.GetUserInput(stdin)
-			if inputError != nil {
-				return inputError
-			}
-			isAnswerCorrect, verificationError := quiz.Verify(question, answerMap, userInput)
-
-			if verificationError == nil {
-				if isAnswerCorrect {
-					fmt.Printf("Your answer is %scorrect%s.\n\n", colorGreen, colorReset)
-					correctAnswers++
-				} else {
-					fmt.Printf("Your answer is %swrong%s. The correct answer is '%s'\n\n", colorRed, colorReset, question.RightAnswer)
-				}
-				break
-			} else {
-				fmt.Println(verificationError)
+	answer, err := game.readAnswer(question, answerMap)
+	if err != nil && answer.AnswerMap == nil {
+		return false, err
+	}
+	game.renderer.AnswerVerdict(verdictEvent(answer))
+	game.recordAnswer(answer)
+
+	// The player may quit right after answering
+	return answer.Correct, err
+}
+
+func (game *game) showQuestion(question Question, number int, numberQuestions int) map[string]string {
+	answerMap := game.quiz.GetAnswerMap(question)
+	game.renderQuestion(question, answerMap, number, numberQuestions)
+
+	return answerMap
+}
+
+func (game *game) renderQuestion(question Question, answerMap map[string]string, number int, numberQuestions int) {
+	game.renderer.QuestionShown(QuestionEvent{
+		Number:          number,
+		NumberQuestions: numberQuestions,
+		Category:        question.Category,
+		Difficulty:      question.Difficulty,
+		Question:        question.Question,
+		Content:         question.RichContent(),
+		Options:         answerMap,
+		Text:            game.quiz.FormatQuestion(question, answerMap),
+	})
+}
+
+// Reads user input until it is a valid answer to the question or the
+// question is skipped. Help and the score are shown in between. The answer
+// comes with ErrQuit when the player quits while it is verified.
+func (game *game) readAnswer(question Question, answerMap map[string]string) (AnswerRecord, error) {
+	asked := time.Now()
+	var deadline time.Time
+	if game.timeLimit > 0 {
+		deadline = asked.Add(game.timeLimit)
+	}
+
+	for {
+		userInput, inputError := game.readInput(deadline)
+		if inputError == ErrTimeUp {
+			if listener, ok := game.quiz.(timeUpListener); ok {
+				listener.TimeUp()
 			}
+			return AnswerRecord{
+				Round:     game.round,
+				Question:  question,
+				AnswerMap: answerMap,
+				TimedOut:  true,
+				Duration:  time.Since(asked),
+			}, nil
+		}
+		if inputError != nil {
+			return AnswerRecord{}, inputError
+		}
+
+		switch parseCommand(userInput, game.localizer) {
+		case CommandQuit:
+			return AnswerRecord{}, ErrQuit
+		case CommandSkip:
+			return AnswerRecord{
+				Round:     game.round,
+				Question:  question,
+				AnswerMap: answerMap,
+				Skipped:   true,
+				Duration:  time.Since(asked),
+			}, nil
+		case CommandHelp:
+			game.renderer.Message(game.help(answerMap) + "\n")
+			continue
+		case CommandScore:
+			game.renderer.Message(game.localizer.Plural("score", game.record.NumberQuestions,
+				game.record.CorrectAnswers, game.record.NumberQuestions) + "\n")
+			continue
+		}
+
+		isAnswerCorrect, verificationError := game.quiz.Verify(question, answerMap, userInput)
+		choice := NormalizeChoice(userInput)
 
+		if verificationError == nil || verificationError == ErrQuit {
+			return AnswerRecord{
+				Round:     game.round,
+				Question:  question,
+				AnswerMap: answerMap,
+				Choice:    choice,
+				Answer:    answerMap[choice],
+				Correct:   isAnswerCorrect,
+				Duration:  time.Since(asked),
+			}, verificationError
 		}
+		game.renderer.Message(verificationError.Error() + "\n")
 	}
+}
 
-	formattedResult := quiz.FormatResult(correctAnswers, len(questions))
-	fmt.Println(formattedResult)
+// Reads the next user input. Returns ErrQuit when the game is interrupted and
+// ErrTimeUp when the deadline passes before the player is done typing; a zero
+// deadline means no limit.
+func (game *game) readInput(deadline time.Time) (string, error) {
+	if game.interrupt == nil && deadline.IsZero() && game.pending == nil {
+		return game.quiz.GetUserInput(game.stdin)
+	}
 
-	return nil
+	// Input is read only once at a time, what is typed after the time was up
+	// answers the next question
+	if game.pending == nil {
+		game.pending = make(chan userInput, 1)
+		go func(pending chan<- userInput) {
+			text, err := game.quiz.GetUserInput(game.stdin)
+			pending <- userInput{text: text, err: err}
+		}(game.pending)
+	}
+
+	var timeUp <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeUp = timer.C
+	}
+
+	select {
+	case received := <-game.pending:
+		game.pending = nil
+		return received.text, received.err
+	case <-game.interrupt:
+		return "", ErrQuit
+	case <-timeUp:
+		return "", ErrTimeUp
+	}
+}
+
+// Returns how to answer and which commands there are
+func (game *game) help(answerMap map[string]string) string {
+	labels := SortedLabels(answerMap)
+	options := strings.Join(labels, ", ")
+	if len(labels) > 1 {
+		options = labels[0] + "-" + labels[len(labels)-1]
+	}
+	return game.localizer.Text("help", options,
+		game.localizer.Text("command_"+CommandSkip), game.localizer.Text("command_"+CommandScore), game.localizer.Text("command_"+CommandQuit))
+}
+
+func verdictEvent(answer AnswerRecord) VerdictEvent {
+	return VerdictEvent{
+		Player:      answer.Player,
+		Team:        answer.Team,
+		Question:    answer.Question.Question,
+		Choice:      answer.Choice,
+		Answer:      answer.Answer,
+		RightAnswer: answer.Question.RightAnswer,
+		Correct:     answer.Correct,
+		Skipped:     answer.Skipped,
+		TimedOut:    answer.TimedOut,
+		Explanation: answer.Question.Explanation,
+		Source:      answer.Question.Source,
+		Content:     answer.Question.RichContent(),
+	}
+}
+
+// Reveals the right answer after several players or teams have answered
+func (game *game) reveal(question Question, answers []VerdictEvent) {
+	game.renderer.AnswersRevealed(RevealEvent{
+		Question:    question.Question,
+		RightAnswer: question.RightAnswer,
+		Explanation: question.Explanation,
+		Source:      question.Source,
+		Answers:     answers,
+	})
+}
+
+func (game *game) recordAnswer(answer AnswerRecord) {
+	game.record.Answers = append(game.record.Answers, answer)
+	game.record.NumberQuestions++
+	if answer.Correct {
+		game.record.CorrectAnswers++
+	}
 }
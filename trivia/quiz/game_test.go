@@ -1,11 +1,13 @@
 package quiz
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"io"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -23,8 +25,8 @@ func (quizMock *QuizMock) GetQuestions(configuration Configuration) ([]Question,
 	return args.Get(0).([]Question), args.Error(1)
 }
 
-func (quizMock *QuizMock) GetAnswerMap(question Question, randomizeAnswers bool) map[string]string {
-	args := quizMock.Called(question, randomizeAnswers)
+func (quizMock *QuizMock) GetAnswerMap(question Question) map[string]string {
+	args := quizMock.Called(question)
 	return args.Get(0).(map[string]string)
 }
 
@@ -52,7 +54,7 @@ func TestRun_AnswerCorrect(t *testing.T) {
 	quizMock := &QuizMock{}
 	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
 	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion}, nil)
-	quizMock.On("GetAnswerMap", mock.Anything, mock.Anything).Return(testAnswerMap)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
 	quizMock.On("GetUserInput", mock.Anything).Return("2", nil)
 	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
 	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
@@ -61,11 +63,11 @@ func TestRun_AnswerCorrect(t *testing.T) {
 	var stdin bytes.Buffer
 	stdin.Write([]byte("dummy"))
 
-	run(quizMock, &stdin)
+	Run(quizMock, &stdin)
 
 	quizMock.AssertNumberOfCalls(t, "ReadConfigurationFromYAML", 1)
 	quizMock.AssertNumberOfCalls(t, "GetQuestions", 1)
-	quizMock.AssertCalled(t, "GetAnswerMap", testQuestion, true)
+	quizMock.AssertCalled(t, "GetAnswerMap", testQuestion)
 	quizMock.AssertNumberOfCalls(t, "GetAnswerMap", 1)
 	quizMock.AssertCalled(t, "GetUserInput", &stdin)
 	quizMock.AssertNumberOfCalls(t, "GetUserInput", 1)
@@ -81,7 +83,7 @@ func TestRun_AnswerWrong(t *testing.T) {
 	quizMock := &QuizMock{}
 	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
 	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion}, nil)
-	quizMock.On("GetAnswerMap", mock.Anything, mock.Anything).Return(testAnswerMap)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
 	quizMock.On("GetUserInput", mock.Anything).Return("1", nil)
 	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
 	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
@@ -90,11 +92,11 @@ func TestRun_AnswerWrong(t *testing.T) {
 	var stdin bytes.Buffer
 	stdin.Write([]byte("dummy"))
 
-	run(quizMock, &stdin)
+	Run(quizMock, &stdin)
 
 	quizMock.AssertNumberOfCalls(t, "ReadConfigurationFromYAML", 1)
 	quizMock.AssertNumberOfCalls(t, "GetQuestions", 1)
-	quizMock.AssertCalled(t, "GetAnswerMap", testQuestion, true)
+	quizMock.AssertCalled(t, "GetAnswerMap", testQuestion)
 	quizMock.AssertNumberOfCalls(t, "GetAnswerMap", 1)
 	quizMock.AssertCalled(t, "GetUserInput", &stdin)
 	quizMock.AssertNumberOfCalls(t, "GetUserInput", 1)
@@ -110,7 +112,7 @@ func TestRun_AnswerInvalid(t *testing.T) {
 	quizMock := &QuizMock{}
 	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
 	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion}, nil)
-	quizMock.On("GetAnswerMap", mock.Anything, mock.Anything).Return(testAnswerMap)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
 	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
 	quizMock.On("GetUserInput", mock.Anything).Return("bad input", nil).Once()
 	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(false, fmt.Errorf("mock error")).Once()
@@ -121,11 +123,11 @@ func TestRun_AnswerInvalid(t *testing.T) {
 	var stdin bytes.Buffer
 	stdin.Write([]byte("dummy"))
 
-	run(quizMock, &stdin)
+	Run(quizMock, &stdin)
 
 	quizMock.AssertNumberOfCalls(t, "ReadConfigurationFromYAML", 1)
 	quizMock.AssertNumberOfCalls(t, "GetQuestions", 1)
-	quizMock.AssertCalled(t, "GetAnswerMap", testQuestion, true)
+	quizMock.AssertCalled(t, "GetAnswerMap", testQuestion)
 	quizMock.AssertNumberOfCalls(t, "GetAnswerMap", 1)
 	quizMock.AssertCalled(t, "GetUserInput", &stdin)
 	quizMock.AssertNumberOfCalls(t, "GetUserInput", 2)
@@ -142,11 +144,11 @@ func TestRun_MultipleQuestions(t *testing.T) {
 	quizMock := &QuizMock{}
 	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
 	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion, testQuestion2}, nil)
-	quizMock.On("GetAnswerMap", mock.Anything, mock.Anything).Return(testAnswerMap).Once()
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap).Once()
 	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
 	quizMock.On("GetUserInput", mock.Anything).Return("2", nil).Once()
 	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
-	quizMock.On("GetAnswerMap", mock.Anything, mock.Anything).Return(testAnswerMap2).Once()
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap2).Once()
 	quizMock.On("GetUserInput", mock.Anything).Return("X", nil).Once()
 	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(false, nil).Once()
 	quizMock.On("FormatResult", mock.Anything, mock.Anything).Return("Formatted result")
@@ -154,12 +156,12 @@ func TestRun_MultipleQuestions(t *testing.T) {
 	var stdin bytes.Buffer
 	stdin.Write([]byte("dummy"))
 
-	run(quizMock, &stdin)
+	Run(quizMock, &stdin)
 
 	quizMock.AssertNumberOfCalls(t, "ReadConfigurationFromYAML", 1)
 	quizMock.AssertNumberOfCalls(t, "GetQuestions", 1)
-	quizMock.AssertCalled(t, "GetAnswerMap", testQuestion, true)
-	quizMock.AssertCalled(t, "GetAnswerMap", testQuestion2, true)
+	quizMock.AssertCalled(t, "GetAnswerMap", testQuestion)
+	quizMock.AssertCalled(t, "GetAnswerMap", testQuestion2)
 	quizMock.AssertNumberOfCalls(t, "GetAnswerMap", 2)
 	quizMock.AssertCalled(t, "GetUserInput", &stdin)
 	quizMock.AssertNumberOfCalls(t, "GetUserInput", 2)
@@ -172,3 +174,95 @@ func TestRun_MultipleQuestions(t *testing.T) {
 	quizMock.AssertCalled(t, "FormatResult", 1, 2)
 	quizMock.AssertNumberOfCalls(t, "FormatResult", 1)
 }
+
+func TestRunWithOptions_RecordsHistory(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", "other.yaml").Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion, testQuestion2}, nil)
+	quizMock.On("GetAnswerMap", testQuestion).Return(testAnswerMap)
+	quizMock.On("GetAnswerMap", testQuestion2).Return(testAnswerMap2)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil)
+	quizMock.On("Verify", testQuestion, mock.Anything, mock.Anything).Return(true, nil)
+	quizMock.On("Verify", testQuestion2, mock.Anything, mock.Anything).Return(false, nil)
+	quizMock.On("FormatResult", mock.Anything, mock.Anything).Return("Formatted result")
+
+	history := NewHistory(t.TempDir())
+	var stdin bytes.Buffer
+	err := RunWithOptions(quizMock, &stdin, Options{ConfigFile: "other.yaml", Player: "anna", History: history})
+	assert.NoError(t, err)
+
+	records, _ := history.Records()
+	assert.Len(t, records, 1)
+	assert.Equal(t, "anna", records[0].Player)
+	assert.Equal(t, "classic", records[0].Mode)
+	assert.NotEmpty(t, records[0].ID)
+	assert.Equal(t, 1, records[0].CorrectAnswers)
+	assert.Equal(t, 2, records[0].NumberQuestions)
+	assert.Equal(t, "Go", records[0].Answers[0].Answer)
+	assert.Equal(t, "Green", records[0].Answers[1].Answer)
+	assert.False(t, records[0].Answers[1].Correct)
+}
+
+// Quiz reading the local questions with question shuffling enabled
+type localQuiz struct {
+	*Quiz
+}
+
+func (localQuiz localQuiz) ReadConfigurationFromYAML(yamlFile string) (Configuration, error) {
+	return Configuration{QuestionFile: "testdata/questions.json", ShuffleQuestions: true}, nil
+}
+
+func TestRunWithOptions_SameSeedSameGame(t *testing.T) {
+	history := NewHistory(t.TempDir())
+	for i := 0; i < 2; i++ {
+		// Reading through one buffered reader keeps the later answers
+		stdin := bufio.NewReader(bytes.NewBufferString("1\n1\n1\n"))
+		err := RunWithOptions(localQuiz{NewQuiz(7)}, stdin, Options{Player: "anna", History: history, Seed: 7})
+		assert.NoError(t, err)
+	}
+
+	records, _ := history.Records()
+	assert.Len(t, records, 2)
+	assert.Equal(t, int64(7), records[0].Seed)
+	assert.Len(t, records[0].Answers, 3)
+	for index, answer := range records[0].Answers {
+		assert.Equal(t, answer.Question, records[1].Answers[index].Question)
+		assert.Equal(t, answer.AnswerMap, records[1].Answers[index].AnswerMap)
+		assert.Equal(t, answer.Correct, records[1].Answers[index].Correct)
+	}
+}
+
+func TestRunWithOptions_InputErrorIsNotRecorded(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("GetUserInput", mock.Anything).Return("", io.EOF)
+
+	history := NewHistory(t.TempDir())
+	var stdin bytes.Buffer
+	err := RunWithOptions(quizMock, &stdin, Options{History: history})
+	assert.Equal(t, io.EOF, err)
+
+	records, _ := history.Records()
+	assert.Empty(t, records)
+}
+
+func TestRunWithOptions_Renderer(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("GetUserInput", mock.Anything).Return("2", nil)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("\nFormatted question")
+	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	quizMock.On("FormatResult", mock.Anything, mock.Anything).Return("Formatted result")
+
+	var out bytes.Buffer
+	err := RunWithOptions(quizMock, nil, Options{Renderer: NewPlainRenderer(&out, nil)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1/1\nFormatted question\nYour answer is correct.\n\nFormatted result\n", out.String())
+}
@@ -0,0 +1,204 @@
+package quiz
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const godocURL string = "https://pkg.go.dev/"
+
+// Doc links like [FileSystem] or [*Request.Context]
+var docLink = regexp.MustCompile(`\[(\*?[A-Za-z_][\w.]*)\]`)
+
+// A documented function or method a question can be asked about
+type godocEntry struct {
+	// The answer, e.g. "Get" or "Do"
+	name string
+	// Type of a method, empty for functions
+	owner string
+	// What the doc comment says it does, without the name
+	does     string
+	synopsis string
+}
+
+// Finds the package with the import path, in the standard library or the
+// current module, and reads the documentation of its exported identifiers
+func ReadPackageDoc(importPath string) (*doc.Package, error) {
+	buildPackage, err := build.Import(importPath, ".", 0)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find package %s: %s", importPath, err.Error())
+	}
+
+	fileSet := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range buildPackage.GoFiles {
+		file, err := parser.ParseFile(fileSet, filepath.Join(buildPackage.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse %s: %s", name, err.Error())
+		}
+		files = append(files, file)
+	}
+
+	return doc.NewFromFiles(fileSet, files, importPath)
+}
+
+// Returns "which function does X" questions about the functions and methods
+// of the package whose doc comment starts with their name, as Go doc comments
+// should. The incorrect answers are other functions of the package, methods
+// of the same type and names alike first.
+func GodocQuestions(docPackage *doc.Package) []Question {
+	entries := godocEntries(docPackage)
+
+	var questions []Question
+	for _, entry := range entries {
+		distractors := godocDistractors(entry, entries)
+		if len(distractors) < 3 {
+			continue
+		}
+
+		subject := fmt.Sprintf("%s function", docPackage.ImportPath)
+		anchor := entry.name
+		if entry.owner != "" {
+			subject = fmt.Sprintf("method of %s.%s", docPackage.Name, entry.owner)
+			anchor = entry.owner + "." + entry.name
+		}
+		question := Question{
+			Category:     "Go: " + docPackage.ImportPath,
+			Question:     fmt.Sprintf("Which %s %s?", subject, entry.does),
+			RightAnswer:  entry.name,
+			WrongAnswers: [3]string{distractors[0], distractors[1], distractors[2]},
+			Explanation:  entry.synopsis,
+			Source:       godocURL + docPackage.ImportPath + "#" + anchor,
+		}
+		if ValidateQuestion(question) == nil {
+			questions = append(questions, question)
+		}
+	}
+
+	return questions
+}
+
+func godocEntries(docPackage *doc.Package) []godocEntry {
+	var entries []godocEntry
+	addFuncs := func(funcs []*doc.Func, owner string) {
+		for _, function := range funcs {
+			entry, ok := newGodocEntry(function.Name, owner, function.Doc)
+			if ok {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	addFuncs(docPackage.Funcs, "")
+	for _, docType := range docPackage.Types {
+		// Constructors are functions of the package
+		addFuncs(docType.Funcs, "")
+		addFuncs(docType.Methods, docType.Name)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].owner != entries[j].owner {
+			return entries[i].owner < entries[j].owner
+		}
+		return entries[i].name < entries[j].name
+	})
+	return entries
+}
+
+// Returns the entry when the first sentence of the doc comment tells what
+// the function does without giving its name away
+func newGodocEntry(name string, owner string, comment string) (godocEntry, bool) {
+	if strings.Contains(comment, "Deprecated:") {
+		return godocEntry{}, false
+	}
+	synopsis := docLink.ReplaceAllString(doc.Synopsis(comment), "$1")
+	if !strings.HasPrefix(synopsis, name+" ") {
+		return godocEntry{}, false
+	}
+
+	does := strings.TrimSuffix(strings.TrimPrefix(synopsis, name+" "), ".")
+	// "Close closes the file" gives the name away as well
+	mentionsName := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(name))
+	if does == "" || mentionsName.MatchString(does) {
+		return godocEntry{}, false
+	}
+
+	return godocEntry{name: name, owner: owner, does: does, synopsis: synopsis}, true
+}
+
+// Returns the three most plausible other names for the entry: methods of the
+// same type before other methods, functions before methods for functions, and
+// those described alike and with names sharing a longer prefix first
+func godocDistractors(entry godocEntry, entries []godocEntry) []string {
+	type candidate struct {
+		name  string
+		score int
+	}
+
+	words := descriptionWords(entry.does)
+	var candidates []candidate
+	for _, other := range entries {
+		if other.name == entry.name {
+			continue
+		}
+		score := commonPrefixLength(entry.name, other.name)
+		for word := range descriptionWords(other.does) {
+			if words[word] {
+				score += 2
+			}
+		}
+		if other.owner == entry.owner {
+			score += 100
+		} else if (other.owner == "") == (entry.owner == "") {
+			score += 50
+		}
+		candidates = append(candidates, candidate{name: other.name, score: score})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	var distractors []string
+	seen := map[string]bool{}
+	for _, candidate := range candidates {
+		if seen[candidate.name] {
+			continue
+		}
+		seen[candidate.name] = true
+		distractors = append(distractors, candidate.name)
+		if len(distractors) == 3 {
+			break
+		}
+	}
+	return distractors
+}
+
+// Returns the words of a description that say something, leaving out short
+// ones like "a" and "the"
+func descriptionWords(description string) map[string]bool {
+	words := map[string]bool{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(description), func(char rune) bool {
+		return !(char >= 'a' && char <= 'z')
+	}) {
+		if len(word) > 3 {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+func commonPrefixLength(first string, second string) int {
+	length := 0
+	for length < len(first) && length < len(second) && first[length] == second[length] {
+		length++
+	}
+	return length
+}
@@ -0,0 +1,96 @@
+package quiz
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const godocSource = `package shapes
+
+// Area returns the size of the surface of the [Shape].
+func Area(shape Shape) float64 { return 0 }
+
+// Perimeter returns the length of the outline of the shape.
+func Perimeter(shape Shape) float64 { return 0 }
+
+// Scale makes the shape larger or smaller by a factor.
+func Scale(shape Shape, factor float64) Shape { return shape }
+
+// Rotate turns the shape around its center.
+func Rotate(shape Shape, degrees float64) Shape { return shape }
+
+// Deprecated: Use Scale.
+func Grow(shape Shape) Shape { return shape }
+
+// Returns a copy, the comment does not start with the name.
+func Clone(shape Shape) Shape { return shape }
+
+// Shape is a closed figure.
+type Shape struct{}
+
+// NewShape returns an empty shape.
+func NewShape() Shape { return Shape{} }
+
+// String returns the name of the shape.
+func (shape Shape) String() string { return "" }
+
+// Valid reports whether the shape is closed.
+func (shape Shape) Valid() bool { return true }
+`
+
+func parseGodoc(t *testing.T) *doc.Package {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "shapes.go", godocSource, parser.ParseComments)
+	assert.NoError(t, err)
+	docPackage, err := doc.NewFromFiles(fileSet, []*ast.File{file}, "example.com/shapes")
+	assert.NoError(t, err)
+	return docPackage
+}
+
+func TestGodocQuestions(t *testing.T) {
+	questions := GodocQuestions(parseGodoc(t))
+
+	var names []string
+	for _, question := range questions {
+		names = append(names, question.RightAnswer)
+	}
+	assert.Equal(t, []string{"Area", "NewShape", "Perimeter", "Rotate", "Scale", "String", "Valid"}, names)
+
+	assert.Equal(t, Question{
+		Category:     "Go: example.com/shapes",
+		Question:     "Which example.com/shapes function returns the size of the surface of the Shape?",
+		RightAnswer:  "Area",
+		WrongAnswers: [3]string{"NewShape", "Perimeter", "Rotate"},
+		Explanation:  "Area returns the size of the surface of the Shape.",
+		Source:       "https://pkg.go.dev/example.com/shapes#Area",
+	}, questions[0])
+
+	// Methods of the same type come first, then other functions
+	assert.Equal(t, "Which method of shapes.Shape reports whether the shape is closed?", questions[6].Question)
+	assert.Equal(t, "String", questions[6].WrongAnswers[0])
+	assert.Equal(t, "https://pkg.go.dev/example.com/shapes#Shape.Valid", questions[6].Source)
+}
+
+func TestNewGodocEntry(t *testing.T) {
+	_, ok := newGodocEntry("Close", "", "Close closes the file.")
+	assert.False(t, ok, "The description gives the name away")
+
+	entry, ok := newGodocEntry("Open", "", "Open prepares the named file for reading. More text.")
+	assert.True(t, ok)
+	assert.Equal(t, "prepares the named file for reading", entry.does)
+}
+
+func TestReadPackageDoc(t *testing.T) {
+	docPackage, err := ReadPackageDoc("strings")
+	assert.NoError(t, err)
+	assert.Equal(t, "strings", docPackage.Name)
+	assert.NotEmpty(t, GodocQuestions(docPackage))
+
+	_, err = ReadPackageDoc("example.com/does/not/exist")
+	assert.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package quiz
+
+import (
+	"strings"
+)
+
+const colorMagenta string = "\033[35m"
+const colorCyan string = "\033[36m"
+const colorGray string = "\033[90m"
+
+// Keywords highlighted by language. Code in other languages gets its strings,
+// numbers and comments highlighted.
+var highlightKeywords = map[string][]string{
+	"go": {"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough", "for", "func",
+		"go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select", "struct",
+		"switch", "type", "var"},
+}
+
+// Languages with comments starting with # instead of //
+var hashComments = map[string]bool{"python": true, "sh": true, "bash": true, "shell": true, "yaml": true}
+
+// Returns the code with ANSI colors for keywords, strings, numbers and
+// comments. It is a simple scanner for short snippets, not a full parser.
+func HighlightCode(code string, language string) string {
+	language = strings.ToLower(language)
+	keywords := map[string]bool{}
+	for _, keyword := range highlightKeywords[language] {
+		keywords[keyword] = true
+	}
+	lineComment := "//"
+	if hashComments[language] {
+		lineComment = "#"
+	}
+
+	var builder strings.Builder
+	for index := 0; index < len(code); {
+		char := code[index]
+		end := index + 1
+		color := ""
+
+		switch {
+		case strings.HasPrefix(code[index:], lineComment):
+			end = len(code)
+			if newline := strings.IndexByte(code[index:], '\n'); newline >= 0 {
+				end = index + newline
+			}
+			color = colorGray
+		case lineComment == "//" && strings.HasPrefix(code[index:], "/*"):
+			end = tokenEnd(code, index+2, "*/", false)
+			color = colorGray
+		case char == '"' || char == '\'':
+			end = tokenEnd(code, index+1, string(char), true)
+			color = colorGreen
+		case char == '`':
+			end = tokenEnd(code, index+1, "`", false)
+			color = colorGreen
+		case isDigit(char):
+			for end < len(code) && (isWordChar(code[end]) || code[end] == '.') {
+				end++
+			}
+			color = colorCyan
+		case isWordChar(char):
+			for end < len(code) && isWordChar(code[end]) {
+				end++
+			}
+			if keywords[code[index:end]] {
+				color = colorMagenta
+			}
+		}
+
+		if color == "" {
+			builder.WriteString(code[index:end])
+		} else {
+			builder.WriteString(color + code[index:end] + colorReset)
+		}
+		index = end
+	}
+
+	return builder.String()
+}
+
+// Returns the index after the closing text, searching from start, or the end
+// of the code when it is not closed. With escapes a backslash escapes the
+// next character.
+func tokenEnd(code string, start int, closing string, escapes bool) int {
+	for index := start; index < len(code); index++ {
+		if escapes && code[index] == '\\' {
+			index++
+			continue
+		}
+		if escapes && code[index] == '\n' {
+			// Quotes do not span lines
+			return index
+		}
+		if strings.HasPrefix(code[index:], closing) {
+			return index + len(closing)
+		}
+	}
+	return len(code)
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func isWordChar(char byte) bool {
+	return char == '_' || isDigit(char) || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
@@ -0,0 +1,21 @@
+package quiz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlightCode_Go(t *testing.T) {
+	code := "func main() {\n\tfmt.Println(\"a // b\", 42) // prints\n}"
+
+	assert.Equal(t, colorMagenta+"func"+colorReset+" main() {\n"+
+		"\tfmt.Println("+colorGreen+"\"a // b\""+colorReset+", "+colorCyan+"42"+colorReset+") "+colorGray+"// prints"+colorReset+"\n}",
+		HighlightCode(code, "go"))
+}
+
+func TestHighlightCode_OtherLanguages(t *testing.T) {
+	assert.Equal(t, "for x in "+colorGreen+"'ab'"+colorReset+": "+colorGray+"# loop"+colorReset, HighlightCode("for x in 'ab': # loop", "python"))
+	assert.Equal(t, colorGreen+"`raw\n\"text\"`"+colorReset, HighlightCode("`raw\n\"text\"`", ""))
+	assert.Equal(t, colorGreen+"\"open"+colorReset+"\nnext", HighlightCode("\"open\nnext", ""))
+}
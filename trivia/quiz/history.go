@@ -0,0 +1,264 @@
+package quiz
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AnswerRecord struct {
+	Player    string            `json:"player,omitempty"`
+	Team      string            `json:"team,omitempty"`
+	Round     string            `json:"round,omitempty"`
+	Question  Question          `json:"question"`
+	AnswerMap map[string]string `json:"answer_map"`
+	Choice    string            `json:"choice"`
+	Answer    string            `json:"answer"`
+	Correct   bool              `json:"correct"`
+	Skipped   bool              `json:"skipped,omitempty"`
+	TimedOut  bool              `json:"timed_out,omitempty"`
+	Duration  time.Duration     `json:"duration"`
+}
+
+type GameRecord struct {
+	ID              string         `json:"id"`
+	Player          string         `json:"player"`
+	Started         time.Time      `json:"started"`
+	Duration        time.Duration  `json:"duration"`
+	Mode            string         `json:"mode"`
+	Seed            int64          `json:"seed,omitempty"`
+	Configuration   Configuration  `json:"configuration"`
+	Answers         []AnswerRecord `json:"answers"`
+	CorrectAnswers  int            `json:"correct_answers"`
+	NumberQuestions int            `json:"number_questions"`
+}
+
+// Finished games stored as JSON lines, one game per line
+type History struct {
+	File string
+}
+
+type Profile struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+}
+
+// Player profiles stored as a JSON list
+type Profiles struct {
+	File string
+}
+
+func NewHistory(dataDir string) *History {
+	return &History{File: filepath.Join(dataDir, "history.jsonl")}
+}
+
+func NewProfiles(dataDir string) *Profiles {
+	return &Profiles{File: filepath.Join(dataDir, "profiles.json")}
+}
+
+func newRecordID(started time.Time) string {
+	return strconv.FormatInt(started.UnixNano(), 36)
+}
+
+func (history *History) Append(record GameRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(history.File), 0755)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(history.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// Returns all recorded games, oldest first. A missing file gives no games.
+func (history *History) Records() ([]GameRecord, error) {
+	var records []GameRecord
+
+	file, err := os.Open(history.File)
+	if os.IsNotExist(err) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var record GameRecord
+		err = json.Unmarshal([]byte(line), &record)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse %s line %d: %s", history.File, lineNumber, err.Error())
+		}
+		records = append(records, record)
+	}
+
+	return records, scanner.Err()
+}
+
+// Returns the recorded games of a player, or of everyone when player is empty
+func (history *History) PlayerRecords(player string) ([]GameRecord, error) {
+	records, err := history.Records()
+	if err != nil || player == "" {
+		return records, err
+	}
+
+	var playerRecords []GameRecord
+	for _, record := range records {
+		if record.Player == player {
+			playerRecords = append(playerRecords, record)
+		}
+	}
+
+	return playerRecords, nil
+}
+
+// Returns the game with the given ID or unique ID prefix
+func (history *History) Find(id string) (GameRecord, error) {
+	records, err := history.Records()
+	if err != nil {
+		return GameRecord{}, err
+	}
+
+	var matches []GameRecord
+	for _, record := range records {
+		if record.ID == id {
+			return record, nil
+		}
+		if strings.HasPrefix(record.ID, id) {
+			matches = append(matches, record)
+		}
+	}
+
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	if len(matches) > 1 {
+		return GameRecord{}, fmt.Errorf("Game ID '%s' is ambiguous", id)
+	}
+	return GameRecord{}, fmt.Errorf("No game with ID '%s'", id)
+}
+
+func (profiles *Profiles) List() ([]Profile, error) {
+	var list []Profile
+
+	file, err := ioutil.ReadFile(profiles.File)
+	if os.IsNotExist(err) {
+		return list, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(file, &list)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse %s: %s", profiles.File, err.Error())
+	}
+
+	return list, nil
+}
+
+// Returns the profile of the player, creating it when it does not exist
+func (profiles *Profiles) Ensure(name string) (Profile, error) {
+	if strings.TrimSpace(name) == "" {
+		return Profile{}, fmt.Errorf("Player name must not be empty")
+	}
+
+	list, err := profiles.List()
+	if err != nil {
+		return Profile{}, err
+	}
+	for _, profile := range list {
+		if profile.Name == name {
+			return profile, nil
+		}
+	}
+
+	profile := Profile{Name: name, Created: time.Now()}
+	list = append(list, profile)
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return Profile{}, err
+	}
+	return profile, writeFile(profiles.File, data)
+}
+
+func FormatGameList(records []GameRecord) string {
+	if len(records) == 0 {
+		return "No games recorded."
+	}
+
+	var builder strings.Builder
+	for _, record := range records {
+		builder.WriteString(fmt.Sprintf("%s  %s  %-12s %-9s %d/%d\n",
+			record.ID,
+			record.Started.Format("2006-01-02 15:04"),
+			record.Player,
+			record.Mode,
+			record.CorrectAnswers,
+			record.NumberQuestions))
+	}
+
+	return builder.String()
+}
+
+func FormatGame(record GameRecord) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Game %s\n", record.ID))
+	builder.WriteString(fmt.Sprintf("Player:   %s\n", record.Player))
+	builder.WriteString(fmt.Sprintf("Started:  %s\n", record.Started.Format("2006-01-02 15:04:05")))
+	builder.WriteString(fmt.Sprintf("Mode:     %s\n", record.Mode))
+	builder.WriteString(fmt.Sprintf("Duration: %s\n", record.Duration.Round(time.Second)))
+	builder.WriteString(fmt.Sprintf("Score:    %d of %d\n", record.CorrectAnswers, record.NumberQuestions))
+
+	for index, answer := range record.Answers {
+		verdict := "wrong"
+		if answer.Correct {
+			verdict = "correct"
+		}
+		builder.WriteString(fmt.Sprintf("\n%d. %s\n", index+1, answer.Question.Question))
+		if answer.Round != "" {
+			builder.WriteString(fmt.Sprintf("   Round: %s\n", answer.Round))
+		}
+		// Skipped and timed-out questions have no choice to show
+		given := fmt.Sprintf("%s: %s", answer.Choice, answer.Answer)
+		if answer.Skipped {
+			given = "skipped"
+		} else if answer.TimedOut {
+			given = "timed out"
+		}
+		builder.WriteString(fmt.Sprintf("   Answer: %s (%s, %s)\n",
+			given, verdict, answer.Duration.Round(100*time.Millisecond)))
+		if !answer.Correct {
+			builder.WriteString(fmt.Sprintf("   Correct answer: %s\n", answer.Question.RightAnswer))
+		}
+	}
+
+	return builder.String()
+}
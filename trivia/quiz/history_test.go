@@ -0,0 +1,107 @@
+package quiz
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRecord = GameRecord{
+	ID:      "abc123",
+	Player:  "anna",
+	Started: time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+	Mode:    "classic",
+	Answers: []AnswerRecord{
+		{Question: testQuestion, AnswerMap: testAnswerMap, Choice: "4", Answer: "Go", Correct: true, Duration: time.Second},
+		{Question: testQuestion2, AnswerMap: testAnswerMap2, Choice: "1", Answer: "Pink", Duration: 2 * time.Second},
+	},
+	CorrectAnswers:  1,
+	NumberQuestions: 2,
+}
+
+func TestHistoryRecordsMissingFile(t *testing.T) {
+	records, err := NewHistory(t.TempDir()).Records()
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestHistoryAppendAndFind(t *testing.T) {
+	history := NewHistory(filepath.Join(t.TempDir(), "data"))
+	otherRecord := GameRecord{ID: "abd456", Player: "bert", Mode: "rounds"}
+
+	assert.NoError(t, history.Append(testRecord))
+	assert.NoError(t, history.Append(otherRecord))
+
+	records, err := history.Records()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, testRecord.Answers, records[0].Answers)
+	assert.True(t, testRecord.Started.Equal(records[0].Started))
+
+	records, _ = history.PlayerRecords("bert")
+	assert.Len(t, records, 1)
+	assert.Equal(t, "abd456", records[0].ID)
+
+	record, err := history.Find("abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "anna", record.Player)
+
+	_, err = history.Find("ab")
+	assert.EqualError(t, err, "Game ID 'ab' is ambiguous")
+
+	_, err = history.Find("zzz")
+	assert.EqualError(t, err, "No game with ID 'zzz'")
+}
+
+func TestHistoryRecordsBadLine(t *testing.T) {
+	history := NewHistory(t.TempDir())
+	ioutil.WriteFile(history.File, []byte("{}\nnot json\n"), 0644)
+
+	_, err := history.Records()
+	assert.Error(t, err)
+}
+
+func TestProfilesEnsure(t *testing.T) {
+	profiles := NewProfiles(t.TempDir())
+
+	created, err := profiles.Ensure("bert")
+	assert.NoError(t, err)
+	profiles.Ensure("anna")
+	existing, _ := profiles.Ensure("bert")
+	assert.True(t, created.Created.Equal(existing.Created))
+
+	list, err := profiles.List()
+	assert.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, "anna", list[0].Name)
+
+	_, err = profiles.Ensure(" ")
+	assert.Error(t, err)
+}
+
+func TestFormatGameList(t *testing.T) {
+	assert.Equal(t, "No games recorded.", FormatGameList(nil))
+	assert.Equal(t, "abc123  2021-03-01 12:00  anna         classic   1/2\n", FormatGameList([]GameRecord{testRecord}))
+}
+
+func TestFormatGame(t *testing.T) {
+	formatted := FormatGame(testRecord)
+	assert.Contains(t, formatted, "Score:    1 of 2\n")
+	assert.Contains(t, formatted, "1. Which language is this written in?\n   Answer: 4: Go (correct, 1s)\n")
+	assert.Contains(t, formatted, "   Answer: 1: Pink (wrong, 2s)\n   Correct answer: Green\n")
+}
+
+func TestFormatGame_SkippedAndTimedOut(t *testing.T) {
+	record := testRecord
+	record.Answers = []AnswerRecord{
+		{Question: testQuestion, Skipped: true, Duration: time.Second},
+		{Question: testQuestion2, TimedOut: true, Duration: 20 * time.Second},
+	}
+
+	formatted := FormatGame(record)
+	assert.Contains(t, formatted, "   Answer: skipped (wrong, 1s)\n   Correct answer: Go\n")
+	assert.Contains(t, formatted, "   Answer: timed out (wrong, 20s)\n   Correct answer: Green\n")
+}
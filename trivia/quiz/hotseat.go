@@ -0,0 +1,226 @@
+package quiz
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Hot-seat modes: players take turns answering their own questions, or
+// every player answers each question before the answer is revealed
+const HotSeatTurns string = "turns"
+const HotSeatEach string = "each"
+
+const minimumPlayers int = 2
+const maximumPlayers int = 8
+
+type PlayerScore struct {
+	Rank            int           `json:"rank"`
+	Name            string        `json:"name"`
+	CorrectAnswers  int           `json:"correct_answers"`
+	NumberQuestions int           `json:"number_questions"`
+	Duration        time.Duration `json:"duration"`
+}
+
+func validatePlayers(players []string, hotSeat string) error {
+	if len(players) < minimumPlayers || len(players) > maximumPlayers {
+		return fmt.Errorf("Hot-seat needs %d to %d players, got %d", minimumPlayers, maximumPlayers, len(players))
+	}
+
+	seen := map[string]bool{}
+	for _, player := range players {
+		if strings.TrimSpace(player) == "" {
+			return fmt.Errorf("Player names must not be empty")
+		}
+		if seen[player] {
+			return fmt.Errorf("Player '%s' is given more than once", player)
+		}
+		seen[player] = true
+	}
+
+	if hotSeat != "" && hotSeat != HotSeatTurns && hotSeat != HotSeatEach {
+		return fmt.Errorf("Unknown hot-seat mode '%s', use %s or %s", hotSeat, HotSeatTurns, HotSeatEach)
+	}
+
+	return nil
+}
+
+// Plays a game where several players share the terminal and prints the
+// final ranking
+func (game *game) playHotSeat(configuration Configuration, players []string, hotSeat string) error {
+	questions, err := game.quiz.GetQuestions(configuration)
+	if err != nil {
+		return err
+	}
+	if hotSeat != HotSeatEach {
+		questions, err = turnQuestions(questions, len(players))
+		if err != nil {
+			return err
+		}
+	}
+
+	for index, question := range questions {
+		if hotSeat == HotSeatEach {
+			err = game.askEveryPlayer(question, index+1, len(questions), players)
+		} else {
+			err = game.askPlayer(question, index+1, len(questions), players[index%len(players)])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	scores := PlayerScores(game.record.Answers, players)
+	game.gameOver(FormatRanking(scores, game.localizer), scores)
+
+	return nil
+}
+
+// Returns the questions for taking turns, leaving out the last ones so that
+// every player gets the same number of questions
+func turnQuestions(questions []Question, numberPlayers int) ([]Question, error) {
+	turns := len(questions) / numberPlayers
+	if turns == 0 {
+		return nil, fmt.Errorf("Taking turns needs at least one question per player, got %d questions for %d players",
+			len(questions), numberPlayers)
+	}
+	return questions[:turns*numberPlayers], nil
+}
+
+func (game *game) askPlayer(question Question, number int, numberQuestions int, player string) error {
+	game.renderer.Message("\n" + game.localizer.Text("turn", player) + "\n")
+	answerMap := game.showQuestion(question, number, numberQuestions)
+
+	answer, err := game.readAnswer(question, answerMap)
+	if err != nil {
+		return err
+	}
+	answer.Player = player
+	game.renderer.AnswerVerdict(verdictEvent(answer))
+	game.recordAnswer(answer)
+
+	return nil
+}
+
+// Lets every player answer the question before revealing the right answer
+func (game *game) askEveryPlayer(question Question, number int, numberQuestions int, players []string) error {
+	answerMap := game.showQuestion(question, number, numberQuestions)
+
+	var answers []AnswerRecord
+	for _, player := range players {
+		game.renderer.Message(game.localizer.Text("player_answer", player))
+		answer, err := game.readAnswer(question, answerMap)
+		if err != nil {
+			return err
+		}
+		answer.Player = player
+		answers = append(answers, answer)
+	}
+
+	var verdicts []VerdictEvent
+	for _, answer := range answers {
+		verdicts = append(verdicts, verdictEvent(answer))
+		game.recordAnswer(answer)
+	}
+	game.reveal(question, verdicts)
+
+	return nil
+}
+
+// Returns the score of each player ranked by correct answers, with ties
+// broken by the time taken
+func PlayerScores(answers []AnswerRecord, players []string) []PlayerScore {
+	var scores []PlayerScore
+	for _, player := range players {
+		score := PlayerScore{Name: player}
+		for _, answer := range answers {
+			if answer.Player != player {
+				continue
+			}
+			score.NumberQuestions++
+			score.Duration += answer.Duration
+			if answer.Correct {
+				score.CorrectAnswers++
+			}
+		}
+		scores = append(scores, score)
+	}
+	rankPlayerScores(scores)
+
+	return scores
+}
+
+func rankPlayerScores(scores []PlayerScore) {
+	rankScores(scores,
+		func(index int) (int, time.Duration) { return scores[index].CorrectAnswers, scores[index].Duration },
+		func(index int, rank int) { scores[index].Rank = rank })
+}
+
+// Sorts a slice of scores by correct answers, with ties broken by the time
+// taken, and numbers their ranks. Equal scores share a rank. score returns
+// the correct answers and the time of the score at an index, setRank sets
+// its rank.
+func rankScores(scores interface{}, score func(index int) (int, time.Duration), setRank func(index int, rank int)) {
+	sort.SliceStable(scores, func(i, j int) bool {
+		correctAnswers, duration := score(i)
+		otherCorrectAnswers, otherDuration := score(j)
+		if correctAnswers != otherCorrectAnswers {
+			return correctAnswers > otherCorrectAnswers
+		}
+		return duration < otherDuration
+	})
+
+	previousRank := 0
+	for index := 0; index < reflect.ValueOf(scores).Len(); index++ {
+		rank := index + 1
+		if index > 0 {
+			correctAnswers, duration := score(index)
+			previousCorrectAnswers, previousDuration := score(index - 1)
+			if correctAnswers == previousCorrectAnswers && duration == previousDuration {
+				rank = previousRank
+			}
+		}
+		setRank(index, rank)
+		previousRank = rank
+	}
+}
+
+func FormatRanking(scores []PlayerScore, localizer *Localizer) string {
+	var builder strings.Builder
+	builder.WriteString(localizer.Text("ranking") + "\n")
+	for _, score := range scores {
+		builder.WriteString(localizer.Plural("ranking_entry", score.NumberQuestions,
+			score.Rank, score.Name, score.CorrectAnswers, score.NumberQuestions, score.Duration.Round(time.Second)) + "\n")
+	}
+
+	return builder.String()
+}
+
+// Splits the record of a hot-seat game into one record per player so that
+// history, statistics and leaderboards treat every player separately
+func splitRecord(record GameRecord, players []string) []GameRecord {
+	var records []GameRecord
+	for index, player := range players {
+		playerRecord := record
+		playerRecord.ID = fmt.Sprintf("%s-%d", record.ID, index+1)
+		playerRecord.Player = player
+		playerRecord.Answers = nil
+		playerRecord.CorrectAnswers = 0
+		playerRecord.NumberQuestions = 0
+		for _, answer := range record.Answers {
+			if answer.Player != player {
+				continue
+			}
+			playerRecord.Answers = append(playerRecord.Answers, answer)
+			playerRecord.NumberQuestions++
+			if answer.Correct {
+				playerRecord.CorrectAnswers++
+			}
+		}
+		records = append(records, playerRecord)
+	}
+
+	return records
+}
@@ -0,0 +1,200 @@
+package quiz
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidatePlayers(t *testing.T) {
+	assert.NoError(t, validatePlayers([]string{"anna", "bert"}, ""))
+	assert.NoError(t, validatePlayers([]string{"anna", "bert"}, HotSeatEach))
+	assert.EqualError(t, validatePlayers([]string{"anna"}, ""), "Hot-seat needs 2 to 8 players, got 1")
+	assert.Error(t, validatePlayers([]string{"1", "2", "3", "4", "5", "6", "7", "8", "9"}, ""))
+	assert.EqualError(t, validatePlayers([]string{"anna", "anna"}, ""), "Player 'anna' is given more than once")
+	assert.Error(t, validatePlayers([]string{"anna", ""}, ""))
+	assert.Error(t, validatePlayers([]string{"anna", "bert"}, "sometimes"))
+}
+
+func TestPlayerScores(t *testing.T) {
+	answers := []AnswerRecord{
+		{Player: "anna", Correct: true, Duration: 3 * time.Second},
+		{Player: "bert", Correct: true, Duration: 2 * time.Second},
+		{Player: "cecilia", Correct: false, Duration: time.Second},
+		{Player: "anna", Correct: true, Duration: 3 * time.Second},
+		{Player: "bert", Correct: false, Duration: 2 * time.Second},
+	}
+
+	scores := PlayerScores(answers, []string{"anna", "bert", "cecilia", "david"})
+	assert.Equal(t, []PlayerScore{
+		{Rank: 1, Name: "anna", CorrectAnswers: 2, NumberQuestions: 2, Duration: 6 * time.Second},
+		{Rank: 2, Name: "bert", CorrectAnswers: 1, NumberQuestions: 2, Duration: 4 * time.Second},
+		{Rank: 3, Name: "david"},
+		{Rank: 4, Name: "cecilia", NumberQuestions: 1, Duration: time.Second},
+	}, scores)
+}
+
+func TestRankScores_Ties(t *testing.T) {
+	scores := []PlayerScore{
+		{Name: "anna", CorrectAnswers: 1, Duration: time.Second},
+		{Name: "bert", CorrectAnswers: 2, Duration: time.Second},
+		{Name: "cecilia", CorrectAnswers: 1, Duration: time.Second},
+		{Name: "david", CorrectAnswers: 1, Duration: 2 * time.Second},
+	}
+	teamScores := []TeamScore{{Name: "red"}, {Name: "blue"}}
+
+	rankPlayerScores(scores)
+	rankScores(teamScores,
+		func(index int) (int, time.Duration) {
+			return teamScores[index].CorrectAnswers, teamScores[index].Duration
+		},
+		func(index int, rank int) { teamScores[index].Rank = rank })
+
+	var ranks []string
+	for _, score := range scores {
+		ranks = append(ranks, fmt.Sprintf("%d %s", score.Rank, score.Name))
+	}
+	assert.Equal(t, []string{"1 bert", "2 anna", "2 cecilia", "4 david"}, ranks)
+	assert.Equal(t, 1, teamScores[0].Rank)
+	assert.Equal(t, 1, teamScores[1].Rank)
+}
+
+func TestFormatRanking(t *testing.T) {
+	scores := []PlayerScore{
+		{Rank: 1, Name: "anna", CorrectAnswers: 2, NumberQuestions: 2, Duration: 6 * time.Second},
+		{Rank: 1, Name: "bert", CorrectAnswers: 2, NumberQuestions: 2, Duration: 6 * time.Second},
+	}
+	expected := "Final ranking:\n" +
+		"  1. anna: 2 of 2 correct answers (6s)\n" +
+		"  1. bert: 2 of 2 correct answers (6s)\n"
+	assert.Equal(t, expected, FormatRanking(scores, nil))
+}
+
+func TestSplitRecord(t *testing.T) {
+	record := GameRecord{
+		ID:   "abc",
+		Mode: "hotseat",
+		Answers: []AnswerRecord{
+			{Player: "anna", Correct: true},
+			{Player: "bert", Correct: false},
+			{Player: "anna", Correct: false},
+		},
+	}
+
+	records := splitRecord(record, []string{"anna", "bert"})
+	assert.Len(t, records, 2)
+	assert.Equal(t, "abc-1", records[0].ID)
+	assert.Equal(t, "anna", records[0].Player)
+	assert.Equal(t, 1, records[0].CorrectAnswers)
+	assert.Equal(t, 2, records[0].NumberQuestions)
+	assert.Equal(t, "abc-2", records[1].ID)
+	assert.Equal(t, "bert", records[1].Player)
+	assert.Equal(t, 1, records[1].NumberQuestions)
+}
+
+func TestRun_HotSeatTurns(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion, testQuestion2, testQuestion}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil)
+	quizMock.On("Verify", testQuestion, mock.Anything, mock.Anything).Return(true, nil)
+	quizMock.On("Verify", testQuestion2, mock.Anything, mock.Anything).Return(false, nil)
+
+	history := NewHistory(t.TempDir())
+	var stdin bytes.Buffer
+	err := RunWithOptions(quizMock, &stdin, Options{Players: []string{"anna", "bert"}, History: history})
+
+	assert.NoError(t, err)
+	// The third question would give anna one more than bert
+	quizMock.AssertNumberOfCalls(t, "Verify", 2)
+	quizMock.AssertNotCalled(t, "FormatResult", mock.Anything, mock.Anything)
+
+	records, _ := history.Records()
+	assert.Len(t, records, 2)
+	assert.Equal(t, "hotseat", records[0].Mode)
+	assert.Equal(t, "anna", records[0].Player)
+	assert.Equal(t, 1, records[0].CorrectAnswers)
+	assert.Equal(t, 1, records[0].NumberQuestions)
+	assert.Equal(t, "bert", records[1].Player)
+	assert.Equal(t, 0, records[1].CorrectAnswers)
+	assert.Equal(t, 1, records[1].NumberQuestions)
+}
+
+func TestRun_HotSeatQuit(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion, testQuestion2}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil).Once()
+	quizMock.On("GetUserInput", mock.Anything).Return("quit", nil).Once()
+	quizMock.On("Verify", mock.Anything, mock.Anything, "4").Return(true, nil)
+
+	var out bytes.Buffer
+	var stdin bytes.Buffer
+	err := RunWithOptions(quizMock, &stdin, Options{Players: []string{"anna", "bert"}, Renderer: NewPlainRenderer(&out, nil)})
+
+	assert.NoError(t, err)
+	quizMock.AssertNotCalled(t, "FormatResult", mock.Anything, mock.Anything)
+	assert.Contains(t, out.String(), "Game quit.\nFinal ranking:\n"+
+		"  1. anna: 1 of 1 correct answer (0s)\n"+
+		"  2. bert: 0 of 0 correct answers (0s)\n")
+}
+
+func TestTurnQuestions(t *testing.T) {
+	questions := []Question{testQuestion, testQuestion2, testQuestion, testQuestion2, testQuestion}
+
+	turns, err := turnQuestions(questions, 2)
+	assert.NoError(t, err)
+	assert.Len(t, turns, 4)
+
+	turns, err = turnQuestions(questions, 5)
+	assert.NoError(t, err)
+	assert.Len(t, turns, 5)
+
+	_, err = turnQuestions(questions, 6)
+	assert.Error(t, err)
+}
+
+func TestRun_HotSeatEach(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil).Once()
+	quizMock.On("GetUserInput", mock.Anything).Return("1", nil).Once()
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil).Once()
+	quizMock.On("Verify", mock.Anything, mock.Anything, "4").Return(true, nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, "1").Return(false, nil)
+
+	history := NewHistory(t.TempDir())
+	var stdin bytes.Buffer
+	err := RunWithOptions(quizMock, &stdin, Options{Players: []string{"anna", "bert", "cecilia"}, HotSeat: HotSeatEach, History: history})
+
+	assert.NoError(t, err)
+	quizMock.AssertNumberOfCalls(t, "FormatQuestion", 1)
+	quizMock.AssertNumberOfCalls(t, "GetUserInput", 3)
+
+	records, _ := history.Records()
+	assert.Len(t, records, 3)
+	assert.Equal(t, 1, records[0].CorrectAnswers)
+	assert.Equal(t, 0, records[1].CorrectAnswers)
+	assert.Equal(t, 1, records[2].CorrectAnswers)
+}
+
+func TestRun_HotSeatInvalidPlayers(t *testing.T) {
+	quizMock := &QuizMock{}
+
+	var stdin bytes.Buffer
+	err := RunWithOptions(quizMock, &stdin, Options{Players: []string{"anna"}})
+
+	assert.Error(t, err)
+	quizMock.AssertNotCalled(t, "ReadConfigurationFromYAML", mock.Anything)
+}
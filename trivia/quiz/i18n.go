@@ -0,0 +1,160 @@
+package quiz
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+const DefaultLanguage string = "en"
+
+//go:embed locales/*.json
+var catalogs embed.FS
+
+var defaultMessages = mustReadCatalog(DefaultLanguage)
+
+// Translates the text of the game into a language. Messages missing in the
+// catalog of the language are taken from the English one. A nil Localizer
+// speaks English.
+type Localizer struct {
+	language string
+	messages map[string]string
+}
+
+func readCatalog(language string) (map[string]string, error) {
+	data, err := catalogs.ReadFile(path.Join("locales", language+".json"))
+	if err != nil {
+		return nil, err
+	}
+
+	messages := map[string]string{}
+	err = json.Unmarshal(data, &messages)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse the catalog of '%s': %s", language, err.Error())
+	}
+	return messages, nil
+}
+
+func mustReadCatalog(language string) map[string]string {
+	messages, err := readCatalog(language)
+	if err != nil {
+		panic(err)
+	}
+	return messages
+}
+
+// Returns the languages there are catalogs for
+func Languages() []string {
+	files, _ := catalogs.ReadDir("locales")
+
+	var languages []string
+	for _, file := range files {
+		languages = append(languages, strings.TrimSuffix(file.Name(), ".json"))
+	}
+	sort.Strings(languages)
+
+	return languages
+}
+
+func NewLocalizer(language string) (*Localizer, error) {
+	messages, err := readCatalog(language)
+	if err != nil {
+		return nil, fmt.Errorf("Unsupported language '%s', use %s", language, strings.Join(Languages(), " or "))
+	}
+	return &Localizer{language: language, messages: messages}, nil
+}
+
+// Returns the localizer for the language given with -lang or, when none is
+// given, for the language of the LANG environment variable, falling back to
+// English when there is no catalog for it
+func SelectLocalizer(language string) (*Localizer, error) {
+	if language != "" {
+		return NewLocalizer(language)
+	}
+
+	localizer, err := NewLocalizer(localeLanguage(os.Getenv("LANG")))
+	if err != nil {
+		return NewLocalizer(DefaultLanguage)
+	}
+	return localizer, nil
+}
+
+// Returns the language of a locale like sv_SE.UTF-8
+func localeLanguage(locale string) string {
+	language := strings.ToLower(locale)
+	for _, separator := range []string{".", "@", "_", "-"} {
+		if index := strings.Index(language, separator); index >= 0 {
+			language = language[:index]
+		}
+	}
+	return language
+}
+
+func (localizer *Localizer) Language() string {
+	if localizer == nil {
+		return DefaultLanguage
+	}
+	return localizer.language
+}
+
+// Returns the message with the arguments filled in
+func (localizer *Localizer) Text(key string, args ...interface{}) string {
+	message, found := "", false
+	if localizer != nil {
+		message, found = localizer.messages[key]
+	}
+	if !found {
+		message, found = defaultMessages[key]
+	}
+	if !found {
+		return key
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Returns the form of the message that goes with the count, e.g. "result.one"
+// for a count of one
+func (localizer *Localizer) Plural(key string, count int, args ...interface{}) string {
+	return localizer.Text(key+"."+pluralForm(localizer.Language(), count), args...)
+}
+
+// Returns the plural category of the count. English and Swedish only tell
+// one from the rest.
+func pluralForm(language string, count int) string {
+	if count == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// Returns the question in the language when the deck has a translation for
+// it, otherwise the question as it is
+func (question Question) Translate(language string) Question {
+	translation, found := question.Translations[language]
+	if !found {
+		return question
+	}
+
+	translated := question
+	translated.Question = translation.Question
+	translated.RightAnswer = translation.RightAnswer
+	translated.WrongAnswers = translation.WrongAnswers
+	if translation.Explanation != "" {
+		translated.Explanation = translation.Explanation
+	}
+	translated.Translations = nil
+	return translated
+}
+
+// Returns the questions in the language where the deck has translations
+func TranslateQuestions(questions []Question, language string) []Question {
+	var translated []Question
+	for _, question := range questions {
+		translated = append(translated, question.Translate(language))
+	}
+	return translated
+}
@@ -0,0 +1,133 @@
+package quiz
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var translatedQuestion = Question{
+	Question:     "Which color has the sky?",
+	RightAnswer:  "Blue",
+	WrongAnswers: [3]string{"Red", "Green", "Pink"},
+	Translations: map[string]Translation{
+		"sv": {Question: "Vilken färg har himlen?", RightAnswer: "Blå", WrongAnswers: [3]string{"Röd", "Grön", "Rosa"}},
+	},
+}
+
+func swedish(t *testing.T) *Localizer {
+	localizer, err := NewLocalizer("sv")
+	assert.NoError(t, err)
+	return localizer
+}
+
+func TestLanguages(t *testing.T) {
+	assert.Equal(t, []string{"en", "sv"}, Languages())
+}
+
+func TestNewLocalizer_Unsupported(t *testing.T) {
+	_, err := NewLocalizer("de")
+
+	assert.EqualError(t, err, "Unsupported language 'de', use en or sv")
+}
+
+func TestSelectLocalizer(t *testing.T) {
+	lang := os.Getenv("LANG")
+	defer os.Setenv("LANG", lang)
+
+	os.Setenv("LANG", "sv_SE.UTF-8")
+	localizer, err := SelectLocalizer("")
+	assert.NoError(t, err)
+	assert.Equal(t, "sv", localizer.Language())
+
+	localizer, err = SelectLocalizer("en")
+	assert.NoError(t, err)
+	assert.Equal(t, "en", localizer.Language())
+
+	os.Setenv("LANG", "de_DE.UTF-8")
+	localizer, err = SelectLocalizer("")
+	assert.NoError(t, err)
+	assert.Equal(t, "en", localizer.Language())
+
+	os.Setenv("LANG", "C")
+	localizer, err = SelectLocalizer("")
+	assert.NoError(t, err)
+	assert.Equal(t, "en", localizer.Language())
+}
+
+func TestLocalizer_Plural(t *testing.T) {
+	localizer := swedish(t)
+
+	assert.Equal(t, "Du hade 1 rätt av 1 fråga.", localizer.Plural("result", 1, 1, 1))
+	assert.Equal(t, "Du hade 2 rätt av 3 frågor.", localizer.Plural("result", 3, 2, 3))
+	assert.Equal(t, "You got 0 of 1 correct answer.", (*Localizer)(nil).Plural("result", 1, 0, 1))
+	assert.Equal(t, "You got 0 of 0 correct answers.", (*Localizer)(nil).Plural("result", 0, 0, 0))
+}
+
+func TestLocalizer_FallsBackToEnglish(t *testing.T) {
+	localizer := &Localizer{language: "sv", messages: map[string]string{}}
+
+	assert.Equal(t, "Team red", localizer.Text("team", "red"))
+	assert.Equal(t, "unknown_key", localizer.Text("unknown_key"))
+}
+
+func TestQuestion_Translate(t *testing.T) {
+	translated := translatedQuestion.Translate("sv")
+
+	assert.Equal(t, "Vilken färg har himlen?", translated.Question)
+	assert.Equal(t, "Blå", translated.RightAnswer)
+	assert.Equal(t, [3]string{"Röd", "Grön", "Rosa"}, translated.WrongAnswers)
+	assert.Nil(t, translated.Translations)
+	assert.Equal(t, translatedQuestion, translatedQuestion.Translate("en"))
+}
+
+func TestQuestion_TranslateExplanation(t *testing.T) {
+	explained := translatedQuestion
+	explained.Explanation = "Blue light scatters the most."
+	assert.Equal(t, "Blue light scatters the most.", explained.Translate("sv").Explanation)
+
+	explained.Translations = map[string]Translation{"sv": {Explanation: "Blått ljus sprids mest."}}
+	assert.Equal(t, "Blått ljus sprids mest.", explained.Translate("sv").Explanation)
+}
+
+func TestQuiz_Swedish(t *testing.T) {
+	quiz := NewQuizWithShuffler(reverseShuffler{})
+	quiz.SetLocalizer(swedish(t))
+	question := translatedQuestion.Translate("sv")
+	answerMap := map[string]string{"1": "Röd", "2": "Blå", "3": "Grön", "4": "Rosa"}
+
+	assert.Equal(t, "\nFråga: Vilken färg har himlen?\n1: Röd\n2: Blå\n3: Grön\n4: Rosa\nSvar: ", quiz.FormatQuestion(question, answerMap))
+	correct, err := quiz.Verify(question, answerMap, "2")
+	assert.NoError(t, err)
+	assert.True(t, correct)
+	_, err = quiz.Verify(question, answerMap, "9")
+	assert.EqualError(t, err, "Svaret är inte ett av alternativen: 9")
+}
+
+func TestPlainRenderer_Swedish(t *testing.T) {
+	var out bytes.Buffer
+
+	NewPlainRenderer(&out, swedish(t)).AnswerVerdict(VerdictEvent{RightAnswer: "Blå"})
+
+	assert.Equal(t, "Ditt svar är fel. Rätt svar är 'Blå'\n\n", out.String())
+}
+
+func TestValidateQuestion_Translations(t *testing.T) {
+	assert.NoError(t, ValidateQuestion(translatedQuestion))
+
+	broken := translatedQuestion
+	broken.Translations = map[string]Translation{"sv": {Question: "Vilken färg har himlen?", RightAnswer: "Blå"}}
+	assert.EqualError(t, ValidateQuestion(broken), "Translation 'sv': The question needs a correct answer and three incorrect answers")
+}
+
+func TestFormatDue_Swedish(t *testing.T) {
+	assert.Equal(t, "2 av 5 kort ska repeteras i dag.", FormatDue(2, 5, swedish(t)))
+}
+
+func TestFormatRoundScores_Swedish(t *testing.T) {
+	text := formatRoundScores([]RoundScore{{Name: "Musik", CorrectAnswers: 1, NumberQuestions: 2}}, swedish(t))
+
+	assert.Equal(t, "\nPoäng per omgång:\n  Musik: 1 av 2\n", text)
+}
@@ -0,0 +1,71 @@
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Label schemes for the answer options
+const LabelsNumbers string = "numbers"
+const LabelsLetters string = "letters"
+
+// Commands the player can type instead of an answer
+const CommandQuit string = "quit"
+const CommandSkip string = "skip"
+const CommandHelp string = "help"
+const CommandScore string = "score"
+
+var commands = []string{CommandQuit, CommandSkip, CommandHelp, CommandScore}
+
+// Returned when the player quits the game
+var ErrQuit = errors.New("The game was quit")
+
+var labelSchemes = map[string][]string{
+	LabelsNumbers: {"1", "2", "3", "4"},
+	LabelsLetters: {"A", "B", "C", "D"},
+}
+
+// Returns the labels of the scheme, numbers when no scheme is given
+func Labels(scheme string) ([]string, error) {
+	if scheme == "" {
+		scheme = LabelsNumbers
+	}
+	labels, found := labelSchemes[scheme]
+	if !found {
+		return nil, fmt.Errorf("Unknown labels '%s', use %s or %s", scheme, LabelsNumbers, LabelsLetters)
+	}
+	return labels, nil
+}
+
+// Returns the labels of the answer map in order
+func SortedLabels(answerMap map[string]string) []string {
+	var labels []string
+	for label := range answerMap {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// Returns the label the player meant, without the space around it and
+// trailing punctuation like in "2." or "b)", and with letters in upper case
+func NormalizeChoice(input string) string {
+	choice := strings.TrimSpace(input)
+	choice = strings.TrimRight(choice, ".,:;)!?")
+	choice = strings.TrimLeft(choice, "(")
+	return strings.ToUpper(strings.TrimSpace(choice))
+}
+
+// Returns the command the input is, in English or in the language of the
+// localizer, or an empty string when it is not a command
+func parseCommand(input string, localizer *Localizer) string {
+	word := strings.ToLower(strings.TrimRight(strings.TrimSpace(input), ".!"))
+	for _, command := range commands {
+		if word == command || word == localizer.Text("command_"+command) {
+			return command
+		}
+	}
+	return ""
+}
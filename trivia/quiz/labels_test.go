@@ -0,0 +1,118 @@
+package quiz
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLabels(t *testing.T) {
+	labels, err := Labels("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3", "4"}, labels)
+
+	labels, err = Labels(LabelsLetters)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A", "B", "C", "D"}, labels)
+
+	_, err = Labels("roman")
+	assert.EqualError(t, err, "Unknown labels 'roman', use numbers or letters")
+}
+
+func TestNormalizeChoice(t *testing.T) {
+	for input, expected := range map[string]string{
+		"2":      "2",
+		" 2 ":    "2",
+		"2.":     "2",
+		"2)\r":   "2",
+		"(3)":    "3",
+		"b":      "B",
+		" c. ":   "C",
+		"d!":     "D",
+		"":       "",
+		"quit?!": "QUIT",
+	} {
+		assert.Equal(t, expected, NormalizeChoice(input), input)
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	assert.Equal(t, CommandQuit, parseCommand(" Quit ", nil))
+	assert.Equal(t, CommandSkip, parseCommand("skip.", nil))
+	assert.Equal(t, CommandHelp, parseCommand("HELP", nil))
+	assert.Equal(t, CommandScore, parseCommand("score", nil))
+	assert.Equal(t, "", parseCommand("2", nil))
+	assert.Equal(t, "", parseCommand("hoppa", nil))
+	assert.Equal(t, CommandSkip, parseCommand("hoppa", swedish(t)))
+	assert.Equal(t, CommandSkip, parseCommand("skip", swedish(t)))
+}
+
+func TestQuiz_Letters(t *testing.T) {
+	quiz := NewQuizWithShuffler(reverseShuffler{})
+	assert.NoError(t, quiz.SetLabels(LabelsLetters))
+
+	answerMap := quiz.GetAnswerMap(testQuestion)
+
+	assert.Equal(t, map[string]string{"A": "Go", "B": "Ruby", "C": "Java", "D": "Python"}, answerMap)
+	assert.Equal(t, "\nQuestion: Which language is this written in?\nA: Go\nB: Ruby\nC: Java\nD: Python\nAnswer: ",
+		quiz.FormatQuestion(testQuestion, answerMap))
+	for _, input := range []string{"A", "a", " a) ", "A."} {
+		correct, err := quiz.Verify(testQuestion, answerMap, input)
+		assert.NoError(t, err, input)
+		assert.True(t, correct, input)
+	}
+	_, err := quiz.Verify(testQuestion, answerMap, "1")
+	assert.EqualError(t, err, "The specified answer is invalid answer: 1")
+}
+
+func TestRunWithOptions_Commands(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion, testQuestion2, testQuestion}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("")
+	quizMock.On("GetUserInput", mock.Anything).Return(" 4. ", nil).Once()
+	quizMock.On("GetUserInput", mock.Anything).Return("help", nil).Once()
+	quizMock.On("GetUserInput", mock.Anything).Return("Score", nil).Once()
+	quizMock.On("GetUserInput", mock.Anything).Return("skip", nil).Once()
+	quizMock.On("GetUserInput", mock.Anything).Return("quit", nil).Once()
+	quizMock.On("Verify", mock.Anything, mock.Anything, " 4. ").Return(true, nil)
+	quizMock.On("FormatResult", 1, 2).Return("Formatted result")
+
+	var out bytes.Buffer
+	history := NewHistory(t.TempDir())
+	err := RunWithOptions(quizMock, nil, Options{Renderer: NewPlainRenderer(&out, nil), History: history})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1/3\nYour answer is correct.\n\n"+
+		"2/3\nAnswer with 1-4, or type skip to skip the question, score to see the score or quit to quit.\n"+
+		"Score: 1 of 1 correct answer so far.\n"+
+		"Skipped. The correct answer is 'Green'\n\n"+
+		"3/3\nGame quit.\nFormatted result\n1 question is left.\n"+
+		"\nQuestions to review:\n- What is blue and yellow together? (using watercolors)\n  The correct answer is 'Green'\n", out.String())
+	quizMock.AssertNumberOfCalls(t, "Verify", 1)
+	records, _ := history.Records()
+	assert.Empty(t, records)
+}
+
+func TestRunWithOptions_SkipIsRecorded(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("")
+	quizMock.On("GetUserInput", mock.Anything).Return("skip", nil)
+	quizMock.On("FormatResult", 0, 1).Return("Formatted result")
+
+	history := NewHistory(t.TempDir())
+	err := RunWithOptions(quizMock, nil, Options{Renderer: NewPlainRenderer(&bytes.Buffer{}, nil), History: history})
+
+	assert.NoError(t, err)
+	records, _ := history.Records()
+	assert.Len(t, records, 1)
+	assert.Equal(t, 1, records[0].NumberQuestions)
+	assert.True(t, records[0].Answers[0].Skipped)
+	assert.False(t, records[0].Answers[0].Correct)
+}
@@ -0,0 +1,170 @@
+package quiz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type LeaderboardFilter struct {
+	Category   string
+	Difficulty string
+	Mode       string
+	// One of "today", "week" or "all". Empty means "all".
+	Window string
+	Now    time.Time
+}
+
+type LeaderboardEntry struct {
+	Rank            int           `json:"rank"`
+	Player          string        `json:"player"`
+	GameID          string        `json:"game_id"`
+	Started         time.Time     `json:"started"`
+	CorrectAnswers  int           `json:"correct_answers"`
+	NumberQuestions int           `json:"number_questions"`
+	Duration        time.Duration `json:"duration"`
+}
+
+// Returns the best game of every player matching the filter, ranked by the
+// number of correct answers with ties broken by the time taken. When a
+// category or difficulty is given only the matching questions are counted.
+func Leaderboard(records []GameRecord, filter LeaderboardFilter, limit int) ([]LeaderboardEntry, error) {
+	since, err := windowStart(filter.Window, filter.Now)
+	if err != nil {
+		return nil, err
+	}
+
+	best := map[string]LeaderboardEntry{}
+	for _, record := range records {
+		if filter.Mode != "" && record.Mode != filter.Mode {
+			continue
+		}
+		if record.Started.Before(since) {
+			continue
+		}
+
+		entry, matched := scoreRecord(record, filter)
+		if !matched {
+			continue
+		}
+		current, found := best[record.Player]
+		if !found || rankedBefore(entry, current) {
+			best[record.Player] = entry
+		}
+	}
+
+	var entries []LeaderboardEntry
+	for _, entry := range best {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if sameScore(entries[i], entries[j]) {
+			if !entries[i].Started.Equal(entries[j].Started) {
+				return entries[i].Started.Before(entries[j].Started)
+			}
+			return entries[i].Player < entries[j].Player
+		}
+		return rankedBefore(entries[i], entries[j])
+	})
+
+	for index := range entries {
+		entries[index].Rank = index + 1
+		if index > 0 && sameScore(entries[index-1], entries[index]) {
+			entries[index].Rank = entries[index-1].Rank
+		}
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	return entries, nil
+}
+
+// Counts the answers of the game that match the category and difficulty of
+// the filter
+func scoreRecord(record GameRecord, filter LeaderboardFilter) (LeaderboardEntry, bool) {
+	entry := LeaderboardEntry{
+		Player:  record.Player,
+		GameID:  record.ID,
+		Started: record.Started,
+	}
+
+	for _, answer := range record.Answers {
+		if !matchesQuestion(record.Configuration, answer.Question, filter.Category, filter.Difficulty) {
+			continue
+		}
+		entry.NumberQuestions++
+		entry.Duration += answer.Duration
+		if answer.Correct {
+			entry.CorrectAnswers++
+		}
+	}
+
+	return entry, entry.NumberQuestions > 0
+}
+
+// A question matches a category either by its name or by the category ID
+// the game was configured with, and likewise for the difficulty
+func matchesQuestion(configuration Configuration, question Question, category string, difficulty string) bool {
+	if category != "" &&
+		!strings.EqualFold(question.Category, category) &&
+		configuration.Trivia.Category != category {
+		return false
+	}
+	if difficulty != "" &&
+		!strings.EqualFold(question.Difficulty, difficulty) &&
+		!(question.Difficulty == "" && strings.EqualFold(configuration.Trivia.Difficulty, difficulty)) {
+		return false
+	}
+
+	return true
+}
+
+func sameScore(entry LeaderboardEntry, other LeaderboardEntry) bool {
+	return entry.CorrectAnswers == other.CorrectAnswers && entry.Duration == other.Duration
+}
+
+func rankedBefore(entry LeaderboardEntry, other LeaderboardEntry) bool {
+	if entry.CorrectAnswers != other.CorrectAnswers {
+		return entry.CorrectAnswers > other.CorrectAnswers
+	}
+	return entry.Duration < other.Duration
+}
+
+// Returns the start of the time window: midnight for "today", Monday
+// midnight for "week" and the zero time for "all"
+func windowStart(window string, now time.Time) (time.Time, error) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch window {
+	case "", "all":
+		return time.Time{}, nil
+	case "today":
+		return midnight, nil
+	case "week":
+		daysSinceMonday := (int(now.Weekday()) + 6) % 7
+		return midnight.AddDate(0, 0, -daysSinceMonday), nil
+	}
+
+	return time.Time{}, fmt.Errorf("Unknown time window '%s', use today, week or all", window)
+}
+
+func FormatLeaderboard(entries []LeaderboardEntry) string {
+	if len(entries) == 0 {
+		return "No games match."
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%-4s %-20s %-7s %-8s %s\n", "Rank", "Player", "Score", "Time", "Played"))
+	for _, entry := range entries {
+		builder.WriteString(fmt.Sprintf("%-4d %-20s %-7s %-8s %s\n",
+			entry.Rank,
+			entry.Player,
+			fmt.Sprintf("%d/%d", entry.CorrectAnswers, entry.NumberQuestions),
+			entry.Duration.Round(time.Second),
+			entry.Started.Format("2006-01-02")))
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,122 @@
+package quiz
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Monday
+var testNow = time.Date(2021, 3, 8, 15, 0, 0, 0, time.UTC)
+
+var scienceQuestion = Question{Question: "Science?", Category: "Science & Nature", Difficulty: "easy", RightAnswer: "A"}
+var historyQuestion = Question{Question: "History?", Category: "History", Difficulty: "hard", RightAnswer: "B"}
+
+func leaderboardRecord(id string, player string, started time.Time, correct ...bool) GameRecord {
+	record := GameRecord{ID: id, Player: player, Started: started, Mode: "classic"}
+	questions := []Question{scienceQuestion, historyQuestion}
+	for index, isAnswerCorrect := range correct {
+		record.Answers = append(record.Answers, AnswerRecord{
+			Question: questions[index%2],
+			Correct:  isAnswerCorrect,
+			Duration: time.Duration(index+1) * time.Second,
+		})
+	}
+	return record
+}
+
+func TestLeaderboardRanksBestGamePerPlayer(t *testing.T) {
+	records := []GameRecord{
+		leaderboardRecord("1", "anna", testNow, true, false),
+		leaderboardRecord("2", "anna", testNow, true, true),
+		leaderboardRecord("3", "bert", testNow, false, true),
+		leaderboardRecord("4", "cecilia", testNow, true, true, false),
+	}
+
+	entries, err := Leaderboard(records, LeaderboardFilter{Now: testNow}, 0)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "anna", entries[0].Player)
+	assert.Equal(t, "2", entries[0].GameID)
+	assert.Equal(t, 3*time.Second, entries[0].Duration)
+	assert.Equal(t, "cecilia", entries[1].Player)
+	assert.Equal(t, 2, entries[1].Rank)
+	assert.Equal(t, "bert", entries[2].Player)
+
+	entries, _ = Leaderboard(records, LeaderboardFilter{Now: testNow}, 1)
+	assert.Len(t, entries, 1)
+}
+
+func TestLeaderboardSharedRank(t *testing.T) {
+	records := []GameRecord{
+		leaderboardRecord("1", "anna", testNow, true),
+		leaderboardRecord("2", "bert", testNow, true),
+	}
+
+	entries, _ := Leaderboard(records, LeaderboardFilter{}, 0)
+	assert.Equal(t, 1, entries[0].Rank)
+	assert.Equal(t, 1, entries[1].Rank)
+}
+
+func TestLeaderboardFilters(t *testing.T) {
+	rounds := leaderboardRecord("3", "cecilia", testNow, true, true)
+	rounds.Mode = "rounds"
+	records := []GameRecord{
+		leaderboardRecord("1", "anna", testNow, true, false),
+		leaderboardRecord("2", "bert", testNow.AddDate(0, 0, -3), false, true),
+		rounds,
+	}
+
+	entries, _ := Leaderboard(records, LeaderboardFilter{Category: "history", Now: testNow}, 0)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "bert", entries[0].Player)
+	assert.Equal(t, 1, entries[0].NumberQuestions)
+	assert.Equal(t, "cecilia", entries[1].Player)
+	assert.Equal(t, 1, entries[1].Rank)
+	assert.Equal(t, "anna", entries[2].Player)
+
+	entries, _ = Leaderboard(records, LeaderboardFilter{Difficulty: "easy", Mode: "classic", Now: testNow}, 0)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "anna", entries[0].Player)
+
+	entries, _ = Leaderboard(records, LeaderboardFilter{Window: "week", Now: testNow}, 0)
+	assert.Len(t, entries, 2)
+
+	entries, _ = Leaderboard(records, LeaderboardFilter{Window: "today", Now: testNow}, 0)
+	assert.Len(t, entries, 2)
+
+	_, err := Leaderboard(records, LeaderboardFilter{Window: "month", Now: testNow}, 0)
+	assert.Error(t, err)
+}
+
+func TestLeaderboardConfiguredCategory(t *testing.T) {
+	record := leaderboardRecord("1", "anna", testNow, true)
+	record.Answers[0].Question.Category = ""
+	record.Configuration.Trivia.Category = "17"
+
+	entries, _ := Leaderboard([]GameRecord{record}, LeaderboardFilter{Category: "17"}, 0)
+	assert.Len(t, entries, 1)
+}
+
+func TestWindowStart(t *testing.T) {
+	sunday := time.Date(2021, 3, 14, 23, 0, 0, 0, time.UTC)
+
+	start, _ := windowStart("week", sunday)
+	assert.Equal(t, time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC), start)
+
+	start, _ = windowStart("today", sunday)
+	assert.Equal(t, time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC), start)
+
+	start, _ = windowStart("all", sunday)
+	assert.True(t, start.IsZero())
+}
+
+func TestFormatLeaderboard(t *testing.T) {
+	assert.Equal(t, "No games match.", FormatLeaderboard(nil))
+
+	entries := []LeaderboardEntry{{Rank: 1, Player: "anna", CorrectAnswers: 2, NumberQuestions: 3, Duration: 12 * time.Second, Started: testNow}}
+	expected := "Rank Player               Score   Time     Played\n" +
+		"1    anna                 2/3     12s      2021-03-08\n"
+	assert.Equal(t, expected, FormatLeaderboard(entries))
+}
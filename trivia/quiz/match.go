@@ -0,0 +1,405 @@
+package quiz
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Event types sent to the players of a match
+const EventLobby string = "lobby"
+const EventQuestion string = "question"
+const EventAnswer string = "answer"
+const EventReveal string = "reveal"
+const EventScoreboard string = "scoreboard"
+const EventGameOver string = "game_over"
+const EventError string = "error"
+
+// Message types sent by players
+const MessageJoin string = "join"
+const MessageAnswer string = "answer"
+
+// Number of events buffered per player. Events to a player that does not
+// keep up are dropped rather than holding up the game.
+const eventBuffer int = 64
+
+type PlayerResult struct {
+	Player  string `json:"player"`
+	Answer  string `json:"answer,omitempty"`
+	Correct bool   `json:"correct"`
+}
+
+type Event struct {
+	Type             string            `json:"type"`
+	Players          []string          `json:"players,omitempty"`
+	Number           int               `json:"number,omitempty"`
+	NumberQuestions  int               `json:"number_questions,omitempty"`
+	Question         string            `json:"question,omitempty"`
+	Content          []ContentBlock    `json:"content,omitempty"`
+	Options          map[string]string `json:"options,omitempty"`
+	TimeLimitSeconds int               `json:"time_limit_seconds,omitempty"`
+	Player           string            `json:"player,omitempty"`
+	RightAnswer      string            `json:"right_answer,omitempty"`
+	Explanation      string            `json:"explanation,omitempty"`
+	Source           string            `json:"source,omitempty"`
+	Results          []PlayerResult    `json:"results,omitempty"`
+	Scores           []PlayerScore     `json:"scores,omitempty"`
+	Message          string            `json:"message,omitempty"`
+}
+
+// Message sent by a player to a match
+type Message struct {
+	Type   string `json:"type"`
+	Player string `json:"player,omitempty"`
+	Choice string `json:"choice,omitempty"`
+}
+
+type matchAnswer struct {
+	player string
+	number int
+	choice string
+}
+
+// Multiplayer game where every question is sent to all players at once and
+// answers are collected until everyone has answered or the time is up.
+// Transports such as TCP or WebSocket connect players with Join and Answer.
+type Match struct {
+	quiz      QuizInterface
+	questions []Question
+	timeLimit time.Duration
+
+	mutex    sync.Mutex
+	players  map[string]chan Event
+	watchers map[int]chan Event
+	watchID  int
+	order    []string
+	number   int
+	closed   bool
+	answers  chan matchAnswer
+	changed  chan struct{}
+	answered []AnswerRecord
+}
+
+func NewMatch(quiz QuizInterface, questions []Question, timeLimit time.Duration) *Match {
+	return &Match{
+		quiz:      quiz,
+		questions: questions,
+		timeLimit: timeLimit,
+		players:   map[string]chan Event{},
+		watchers:  map[int]chan Event{},
+		answers:   make(chan matchAnswer, eventBuffer),
+		changed:   make(chan struct{}, 1),
+	}
+}
+
+// Adds a player to the match and returns the events for that player. The
+// channel is closed when the player leaves or the match is closed.
+func (match *Match) Join(player string) (<-chan Event, error) {
+	match.mutex.Lock()
+	defer match.mutex.Unlock()
+
+	if player == "" {
+		return nil, fmt.Errorf("Player name must not be empty")
+	}
+	if match.closed {
+		return nil, fmt.Errorf("The match is over")
+	}
+	if _, found := match.players[player]; found {
+		return nil, fmt.Errorf("Player '%s' has already joined", player)
+	}
+
+	events := make(chan Event, eventBuffer)
+	match.players[player] = events
+	if !contains(match.order, player) {
+		match.order = append(match.order, player)
+	}
+	match.broadcastLocked(Event{Type: EventLobby, Players: match.connectedLocked()})
+	match.notify()
+
+	return events, nil
+}
+
+func (match *Match) Leave(player string) {
+	match.mutex.Lock()
+	defer match.mutex.Unlock()
+
+	events, found := match.players[player]
+	if !found {
+		return
+	}
+	delete(match.players, player)
+	close(events)
+	match.broadcastLocked(Event{Type: EventLobby, Players: match.connectedLocked()})
+	match.notify()
+}
+
+// Returns all events of the match without taking part, e.g. for a shared
+// screen. The returned ID is used to stop watching.
+func (match *Match) Watch() (int, <-chan Event) {
+	match.mutex.Lock()
+	defer match.mutex.Unlock()
+
+	events := make(chan Event, eventBuffer)
+	match.watchID++
+	if match.closed {
+		close(events)
+		return match.watchID, events
+	}
+	match.watchers[match.watchID] = events
+	send(events, Event{Type: EventLobby, Players: match.connectedLocked()})
+
+	return match.watchID, events
+}
+
+func (match *Match) Unwatch(id int) {
+	match.mutex.Lock()
+	defer match.mutex.Unlock()
+
+	if events, found := match.watchers[id]; found {
+		delete(match.watchers, id)
+		close(events)
+	}
+}
+
+// Submits the answer of a player to the question currently asked
+func (match *Match) Answer(player string, choice string) error {
+	match.mutex.Lock()
+	number := match.number
+	_, found := match.players[player]
+	match.mutex.Unlock()
+
+	if !found {
+		return fmt.Errorf("Player '%s' has not joined", player)
+	}
+	if number == 0 {
+		return fmt.Errorf("No question is open")
+	}
+
+	select {
+	case match.answers <- matchAnswer{player: player, number: number, choice: choice}:
+		return nil
+	default:
+		return fmt.Errorf("Too many answers, try again")
+	}
+}
+
+// Sends an error message to a single player
+func (match *Match) SendError(player string, message string) {
+	match.sendTo(player, Event{Type: EventError, Message: message})
+}
+
+// Returns the connected players in the order they joined
+func (match *Match) Players() []string {
+	match.mutex.Lock()
+	defer match.mutex.Unlock()
+
+	return match.connectedLocked()
+}
+
+// Blocks until at least the given number of players has joined or stop is
+// closed. Returns false when stopped.
+func (match *Match) WaitForPlayers(count int, stop <-chan struct{}) bool {
+	for {
+		if len(match.Players()) >= count {
+			return true
+		}
+		select {
+		case <-match.changed:
+		case <-stop:
+			return false
+		}
+	}
+}
+
+// Asks all questions and returns the final scores. Players that join while
+// the match is running take part from the next question.
+func (match *Match) Play() []PlayerScore {
+	for index, question := range match.questions {
+		answerMap := match.quiz.GetAnswerMap(question)
+
+		match.mutex.Lock()
+		match.number = index + 1
+		match.broadcastLocked(Event{
+			Type:             EventQuestion,
+			Number:           index + 1,
+			NumberQuestions:  len(match.questions),
+			Question:         question.Question,
+			Content:          question.RichContent(),
+			Options:          answerMap,
+			TimeLimitSeconds: int(match.timeLimit.Seconds()),
+		})
+		match.mutex.Unlock()
+
+		answers := match.collect(index+1, question, answerMap)
+
+		match.mutex.Lock()
+		match.number = 0
+		match.answered = append(match.answered, answers...)
+		match.broadcastLocked(revealEvent(question, answers))
+		match.broadcastLocked(Event{Type: EventScoreboard, Scores: match.scoresLocked()})
+		match.mutex.Unlock()
+	}
+
+	match.mutex.Lock()
+	defer match.mutex.Unlock()
+	scores := match.scoresLocked()
+	match.broadcastLocked(Event{Type: EventGameOver, Scores: scores})
+
+	return scores
+}
+
+// Ends the match and closes the event channels of all players
+func (match *Match) Close() {
+	match.mutex.Lock()
+	defer match.mutex.Unlock()
+
+	match.closed = true
+	for player, events := range match.players {
+		close(events)
+		delete(match.players, player)
+	}
+	for id, events := range match.watchers {
+		close(events)
+		delete(match.watchers, id)
+	}
+}
+
+// Collects answers until all connected players have answered or the time
+// limit is reached
+func (match *Match) collect(number int, question Question, answerMap map[string]string) []AnswerRecord {
+	timer := time.NewTimer(match.timeLimit)
+	defer timer.Stop()
+	asked := time.Now()
+	answered := map[string]bool{}
+	var answers []AnswerRecord
+
+	for !match.allAnswered(answered) {
+		select {
+		case answer := <-match.answers:
+			if answer.number != number {
+				continue
+			}
+			if answered[answer.player] {
+				match.sendTo(answer.player, Event{Type: EventError, Message: "You have already answered this question"})
+				continue
+			}
+			isAnswerCorrect, err := match.quiz.Verify(question, answerMap, answer.choice)
+			if err != nil {
+				match.sendTo(answer.player, Event{Type: EventError, Message: err.Error()})
+				continue
+			}
+			answered[answer.player] = true
+			choice := NormalizeChoice(answer.choice)
+			answers = append(answers, AnswerRecord{
+				Player:    answer.player,
+				Question:  question,
+				AnswerMap: answerMap,
+				Choice:    choice,
+				Answer:    answerMap[choice],
+				Correct:   isAnswerCorrect,
+				Duration:  time.Since(asked),
+			})
+			match.broadcast(Event{Type: EventAnswer, Player: answer.player})
+		case <-match.changed:
+		case <-timer.C:
+			return answers
+		}
+	}
+
+	return answers
+}
+
+func (match *Match) allAnswered(answered map[string]bool) bool {
+	players := match.Players()
+	if len(players) == 0 {
+		return false
+	}
+	for _, player := range players {
+		if !answered[player] {
+			return false
+		}
+	}
+	return true
+}
+
+func revealEvent(question Question, answers []AnswerRecord) Event {
+	event := Event{
+		Type:        EventReveal,
+		Question:    question.Question,
+		RightAnswer: question.RightAnswer,
+		Explanation: question.Explanation,
+		Source:      question.Source,
+	}
+	for _, answer := range answers {
+		event.Results = append(event.Results, PlayerResult{
+			Player:  answer.Player,
+			Answer:  answer.Answer,
+			Correct: answer.Correct,
+		})
+	}
+	return event
+}
+
+func (match *Match) broadcast(event Event) {
+	match.mutex.Lock()
+	defer match.mutex.Unlock()
+
+	match.broadcastLocked(event)
+}
+
+func (match *Match) broadcastLocked(event Event) {
+	for _, events := range match.players {
+		send(events, event)
+	}
+	for _, events := range match.watchers {
+		send(events, event)
+	}
+}
+
+func (match *Match) sendTo(player string, event Event) {
+	match.mutex.Lock()
+	defer match.mutex.Unlock()
+
+	if events, found := match.players[player]; found {
+		send(events, event)
+	}
+}
+
+func send(events chan Event, event Event) {
+	select {
+	case events <- event:
+	default:
+	}
+}
+
+// Wakes up anyone waiting for the players to change
+func (match *Match) notify() {
+	select {
+	case match.changed <- struct{}{}:
+	default:
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (match *Match) connectedLocked() []string {
+	var players []string
+	for _, player := range match.order {
+		if _, found := match.players[player]; found {
+			players = append(players, player)
+		}
+	}
+	return players
+}
+
+// Scores of everyone who has joined, including players that have left
+func (match *Match) scoresLocked() []PlayerScore {
+	return PlayerScores(match.answered, match.order)
+}
@@ -0,0 +1,155 @@
+package quiz
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Returns the next event that is not a lobby or answer notification
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	for {
+		select {
+		case event, open := <-events:
+			if !open {
+				t.Fatal("Event channel closed")
+			}
+			if event.Type != EventLobby && event.Type != EventAnswer {
+				return event
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for an event")
+		}
+	}
+}
+
+func matchQuizMock() *QuizMock {
+	quizMock := &QuizMock{}
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("Verify", mock.Anything, mock.Anything, "4").Return(true, nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, "1").Return(false, nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(false, fmt.Errorf("invalid"))
+	return quizMock
+}
+
+func TestMatchJoinAndLeave(t *testing.T) {
+	match := NewMatch(matchQuizMock(), nil, time.Second)
+
+	annaEvents, err := match.Join("anna")
+	assert.NoError(t, err)
+	_, err = match.Join("bert")
+	assert.NoError(t, err)
+
+	_, err = match.Join("anna")
+	assert.EqualError(t, err, "Player 'anna' has already joined")
+	_, err = match.Join("")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"anna", "bert"}, match.Players())
+
+	assert.Equal(t, Event{Type: EventLobby, Players: []string{"anna"}}, <-annaEvents)
+	assert.Equal(t, Event{Type: EventLobby, Players: []string{"anna", "bert"}}, <-annaEvents)
+
+	match.Leave("anna")
+	_, open := <-annaEvents
+	assert.False(t, open)
+	assert.Equal(t, []string{"bert"}, match.Players())
+
+	assert.True(t, match.WaitForPlayers(1, nil))
+	stop := make(chan struct{})
+	close(stop)
+	assert.False(t, match.WaitForPlayers(3, stop))
+
+	match.Close()
+	_, err = match.Join("cecilia")
+	assert.EqualError(t, err, "The match is over")
+}
+
+func TestMatchAnswerWithoutQuestion(t *testing.T) {
+	match := NewMatch(matchQuizMock(), nil, time.Second)
+	match.Join("anna")
+
+	assert.EqualError(t, match.Answer("anna", "1"), "No question is open")
+	assert.EqualError(t, match.Answer("bert", "1"), "Player 'bert' has not joined")
+}
+
+func TestMatchPlay(t *testing.T) {
+	match := NewMatch(matchQuizMock(), []Question{testQuestion, testQuestion2}, 5*time.Second)
+	annaEvents, _ := match.Join("anna")
+	bertEvents, _ := match.Join("bert")
+
+	done := make(chan []PlayerScore)
+	go func() { done <- match.Play() }()
+
+	question := nextEvent(t, annaEvents)
+	assert.Equal(t, EventQuestion, question.Type)
+	assert.Equal(t, 1, question.Number)
+	assert.Equal(t, 2, question.NumberQuestions)
+	assert.Equal(t, testQuestion.Question, question.Question)
+	assert.Equal(t, testAnswerMap, question.Options)
+	assert.Equal(t, 5, question.TimeLimitSeconds)
+	nextEvent(t, bertEvents)
+
+	assert.NoError(t, match.Answer("anna", "4"))
+	assert.NoError(t, match.Answer("bert", "X"))
+	assert.Equal(t, Event{Type: EventError, Message: "invalid"}, nextEvent(t, bertEvents))
+	assert.NoError(t, match.Answer("bert", "1"))
+
+	reveal := nextEvent(t, annaEvents)
+	assert.Equal(t, EventReveal, reveal.Type)
+	assert.Equal(t, "Go", reveal.RightAnswer)
+	assert.Equal(t, []PlayerResult{
+		{Player: "anna", Answer: "Go", Correct: true},
+		{Player: "bert", Answer: "Ruby", Correct: false},
+	}, reveal.Results)
+	scoreboard := nextEvent(t, annaEvents)
+	assert.Equal(t, EventScoreboard, scoreboard.Type)
+	assert.Equal(t, "anna", scoreboard.Scores[0].Name)
+
+	nextEvent(t, annaEvents)
+	assert.NoError(t, match.Answer("anna", "1"))
+	match.Leave("bert")
+
+	scores := <-done
+	assert.Equal(t, 2, len(scores))
+	assert.Equal(t, "anna", scores[0].Name)
+	assert.Equal(t, 1, scores[0].CorrectAnswers)
+	assert.Equal(t, 2, scores[0].NumberQuestions)
+	assert.Equal(t, "bert", scores[1].Name)
+	assert.Equal(t, 1, scores[1].NumberQuestions)
+
+	nextEvent(t, annaEvents)
+	nextEvent(t, annaEvents)
+	gameOver := nextEvent(t, annaEvents)
+	assert.Equal(t, EventGameOver, gameOver.Type)
+	assert.Equal(t, scores, gameOver.Scores)
+}
+
+func TestMatchNormalizesTheChoice(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("Verify", mock.Anything, mock.Anything, " 4. ").Return(true, nil)
+	match := NewMatch(quizMock, []Question{testQuestion}, 5*time.Second)
+	annaEvents, _ := match.Join("anna")
+
+	done := make(chan []PlayerScore)
+	go func() { done <- match.Play() }()
+
+	nextEvent(t, annaEvents)
+	assert.NoError(t, match.Answer("anna", " 4. "))
+
+	reveal := nextEvent(t, annaEvents)
+	assert.Equal(t, []PlayerResult{{Player: "anna", Answer: "Go", Correct: true}}, reveal.Results)
+	scores := <-done
+	assert.Equal(t, 1, scores[0].CorrectAnswers)
+}
+
+func TestMatchTimeLimit(t *testing.T) {
+	match := NewMatch(matchQuizMock(), []Question{testQuestion}, 10*time.Millisecond)
+	match.Join("anna")
+
+	scores := match.Play()
+	assert.Equal(t, []PlayerScore{{Rank: 1, Name: "anna"}}, scores)
+}
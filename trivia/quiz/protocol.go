@@ -0,0 +1,66 @@
+package quiz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Version of the JSON-lines protocol, sent with every event
+const ProtocolVersion int = 1
+
+// An answer sent by a front-end in the JSON-lines protocol
+type ProtocolAnswer struct {
+	Version int    `json:"version,omitempty"`
+	Choice  string `json:"choice"`
+}
+
+// Wraps a quiz so that the answers are read as JSON objects, one per line.
+// Lines that are not a valid answer are reported as an error event and the
+// next line is read.
+type protocolQuiz struct {
+	QuizInterface
+	renderer *jsonRenderer
+}
+
+// Returns the quiz and the renderer for playing over the JSON-lines protocol,
+// with the events written to out
+func NewProtocol(quiz QuizInterface, out io.Writer) (QuizInterface, Renderer) {
+	renderer := &jsonRenderer{encoder: json.NewEncoder(out), version: ProtocolVersion}
+	return &protocolQuiz{QuizInterface: quiz, renderer: renderer}, renderer
+}
+
+func (protocol *protocolQuiz) GetUserInput(stdin io.Reader) (string, error) {
+	for {
+		line, err := protocol.QuizInterface.GetUserInput(stdin)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		answer, err := parseProtocolAnswer(line)
+		if err != nil {
+			protocol.renderer.error(err.Error())
+			continue
+		}
+		return answer.Choice, nil
+	}
+}
+
+func parseProtocolAnswer(line string) (ProtocolAnswer, error) {
+	var answer ProtocolAnswer
+	err := json.Unmarshal([]byte(line), &answer)
+	if err != nil {
+		return answer, fmt.Errorf("Failed to parse answer '%s': %s", line, err.Error())
+	}
+	if answer.Version != 0 && answer.Version != ProtocolVersion {
+		return answer, fmt.Errorf("Unsupported protocol version %d, this game speaks version %d", answer.Version, ProtocolVersion)
+	}
+	if answer.Choice == "" {
+		return answer, fmt.Errorf("The answer has no choice")
+	}
+	return answer, nil
+}
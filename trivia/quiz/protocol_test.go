@@ -0,0 +1,70 @@
+package quiz
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseProtocolAnswer(t *testing.T) {
+	answer, err := parseProtocolAnswer(`{"version":1,"choice":"2"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", answer.Choice)
+
+	answer, err = parseProtocolAnswer(`{"choice":"3"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", answer.Choice)
+
+	_, err = parseProtocolAnswer(`{"version":2,"choice":"1"}`)
+	assert.EqualError(t, err, "Unsupported protocol version 2, this game speaks version 1")
+
+	_, err = parseProtocolAnswer(`{}`)
+	assert.EqualError(t, err, "The answer has no choice")
+
+	_, err = parseProtocolAnswer(`2`)
+	assert.Error(t, err)
+}
+
+func TestProtocol_GetUserInputSkipsInvalidLines(t *testing.T) {
+	var out bytes.Buffer
+	protocol, _ := NewProtocol(NewQuiz(1), &out)
+	stdin := bufio.NewReader(strings.NewReader("\nnot json\n{\"choice\":\"4\"}\n"))
+
+	choice, err := protocol.GetUserInput(stdin)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "4", choice)
+	assert.True(t, strings.HasPrefix(out.String(), `{"event":"error","version":1,"message":"Failed to parse answer 'not json'`))
+	assert.Equal(t, 1, strings.Count(out.String(), "\n"))
+
+	_, err = protocol.GetUserInput(stdin)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestRunWithOptions_Protocol(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("GetUserInput", mock.Anything).Return(`{"version":1,"choice":"2"}`, nil)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("Verify", mock.Anything, mock.Anything, "2").Return(true, nil)
+	quizMock.On("FormatResult", mock.Anything, mock.Anything).Return("Formatted result")
+
+	var out bytes.Buffer
+	protocol, renderer := NewProtocol(quizMock, &out)
+	err := RunWithOptions(protocol, nil, Options{Renderer: renderer})
+
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], `{"event":"question","version":1,"number":1,"number_questions":1,`))
+	assert.True(t, strings.HasPrefix(lines[1], `{"event":"verdict","version":1,`))
+	assert.Contains(t, lines[1], `"choice":"2"`)
+	assert.Equal(t, `{"event":"game_over","version":1,"correct_answers":1,"number_questions":1}`, lines[2])
+}
@@ -3,6 +3,7 @@ package quiz
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
 	"io"
@@ -10,28 +11,80 @@ import (
 	"math/rand"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
 	"strings"
-	"time"
 
 	"gopkg.in/yaml.v2"
 )
 
 type TriviaObject struct {
-	BaseURL    string `yaml:"base_url"`
-	Amount     string `yaml:"amount"`
-	Category   string `yaml:"category"`
-	Difficulty string `yaml:"difficulty"`
+	BaseURL    string `yaml:"base_url" json:"base_url"`
+	Amount     string `yaml:"amount" json:"amount"`
+	Category   string `yaml:"category" json:"category"`
+	Difficulty string `yaml:"difficulty" json:"difficulty"`
+}
+
+type RoundObject struct {
+	Name       string `yaml:"name" json:"name"`
+	Amount     string `yaml:"amount" json:"amount"`
+	Category   string `yaml:"category" json:"category"`
+	Difficulty string `yaml:"difficulty" json:"difficulty"`
+}
+
+type AdaptiveObject struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	Window  int  `yaml:"window" json:"window"`
+}
+
+type DailyObject struct {
+	Amount int `yaml:"amount" json:"amount"`
+}
+
+type AccessibilityObject struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// How many times longer the time limit is in the accessible mode
+	TimeLimitFactor float64 `yaml:"time_limit_factor" json:"time_limit_factor,omitempty"`
 }
 
 type Configuration struct {
-	QuestionFile string `yaml:"question_file"`
-	Trivia       TriviaObject
+	QuestionFile string         `yaml:"question_file" json:"question_file"`
+	Decks        []string       `yaml:"decks" json:"decks"`
+	DataDir      string         `yaml:"data_dir" json:"data_dir"`
+	Trivia       TriviaObject   `json:"trivia"`
+	Rounds       []RoundObject  `yaml:"rounds" json:"rounds"`
+	Adaptive     AdaptiveObject `yaml:"adaptive" json:"adaptive"`
+	Daily        DailyObject    `yaml:"daily" json:"daily"`
+	// Linear output for screen readers, see accessibility.go
+	Accessibility AccessibilityObject `yaml:"accessibility" json:"accessibility"`
+	// Shuffles the order of the questions, not only of the answers
+	ShuffleQuestions bool `yaml:"shuffle_questions" json:"shuffle_questions"`
+	// Labels of the answer options: numbers or letters
+	Labels string `yaml:"labels" json:"labels,omitempty"`
 }
 
 type Question struct {
+	Category     string    `json:"category,omitempty"`
+	Difficulty   string    `json:"difficulty,omitempty"`
 	Question     string    `json:"question"`
 	RightAnswer  string    `json:"correct_answer"`
 	WrongAnswers [3]string `json:"incorrect_answers"`
+	// Code and images shown after the question text
+	Content []ContentBlock `json:"content,omitempty"`
+	// Why the right answer is right, shown after the verdict
+	Explanation string `json:"explanation,omitempty"`
+	// Link to read more about the answer
+	Source string `json:"source,omitempty"`
+	// The question in other languages, by language
+	Translations map[string]Translation `json:"translations,omitempty"`
+}
+
+// The text of a question in another language
+type Translation struct {
+	Question     string    `json:"question"`
+	RightAnswer  string    `json:"correct_answer"`
+	WrongAnswers [3]string `json:"incorrect_answers"`
+	Explanation  string    `json:"explanation,omitempty"`
 }
 
 type OpenTriviaResponse struct {
@@ -42,39 +95,121 @@ type OpenTriviaResponse struct {
 type QuizInterface interface {
 	ReadConfigurationFromYAML(yamlFile string) (Configuration, error)
 	GetQuestions(configuration Configuration) ([]Question, error)
-	GetAnswerMap(question Question, randomizeAnswers bool) map[string]string
+	GetAnswerMap(question Question) map[string]string
 	GetUserInput(stdin io.Reader) (string, error)
 	FormatQuestion(question Question, answerMap map[string]string) string
 	Verify(question Question, answerMap map[string]string, userInput string) (bool, error)
 	FormatResult(correctAnswers int, numberQuestions int) string
 }
 
+// Shuffles n elements in place, like (*rand.Rand).Shuffle
+type Shuffler interface {
+	Shuffle(n int, swap func(i, j int))
+}
+
 type Quiz struct {
 	questions []Question
+	shuffler  Shuffler
+	localizer *Localizer
+	labels    []string
+	// Sizes of the shuffles made so far, see Draws
+	draws []int
+	// Highlights the code of questions, for renderers with color
+	color bool
+	// Formats questions for screen readers
+	accessible bool
+}
+
+// Returns a quiz that shuffles answers and questions reproducibly from the seed
+func NewQuiz(seed int64) *Quiz {
+	return NewQuizWithShuffler(rand.New(rand.NewSource(seed)))
+}
+
+func NewQuizWithShuffler(shuffler Shuffler) *Quiz {
+	return &Quiz{shuffler: shuffler}
+}
+
+// Sets the language of the questions, where the deck has translations, and
+// of the text around them
+func (quiz *Quiz) SetLocalizer(localizer *Localizer) {
+	quiz.localizer = localizer
+}
+
+// Makes FormatQuestion highlight code, for output with color
+func (quiz *Quiz) SetColor(color bool) {
+	quiz.color = color
+}
+
+// Sets the label scheme of the answer options, numbers or letters
+func (quiz *Quiz) SetLabels(scheme string) error {
+	labels, err := Labels(scheme)
+	if err != nil {
+		return err
+	}
+	quiz.labels = labels
+	return nil
 }
 
 func (quiz *Quiz) GetQuestions(configuration Configuration) ([]Question, error) {
 	triviaUrl, err := quiz.createTriviaURL(configuration)
 	if err != nil {
-		fmt.Printf("Failed to build URL: %s\n", err.Error())
+		fmt.Fprintf(os.Stderr, "Failed to build URL: %s\n", err.Error())
 	}
 	// TODO: Avoid always reading from URL? (if createTriviaURL failed, for instance)
 	questions, err := quiz.readQuestionsFromURL(triviaUrl)
-	if err != nil {
-		fmt.Printf("Failed to read OpenTrivia, reading from file: %s\n", err.Error())
+	offline := err != nil
+	if offline {
+		fmt.Fprintf(os.Stderr, "Failed to read OpenTrivia, reading from file: %s\n", err.Error())
 		questions, err = quiz.readQuestionsFromJSON(configuration.QuestionFile)
 		if err != nil {
 			return nil, err
 		}
+		questions = matchingQuestions(questions, configuration.Trivia)
+	}
+
+	if configuration.ShuffleQuestions {
+		quiz.shuffle(len(questions), func(i, j int) { questions[i], questions[j] = questions[j], questions[i] })
+	}
+	// The local file holds more questions than asked for, like OpenTrivia
+	// would return only the amount
+	amount, err := strconv.Atoi(configuration.Trivia.Amount)
+	if offline && err == nil && amount > 0 && amount < len(questions) {
+		questions = questions[:amount]
+	}
+	if quiz.localizer != nil {
+		questions = TranslateQuestions(questions, quiz.localizer.Language())
 	}
 
 	return questions, nil
 }
 
+// Returns the local questions of the category and difficulty of the trivia
+// settings, by name. Questions without a category or difficulty are kept, the
+// local file does not tell which one they have.
+func matchingQuestions(questions []Question, trivia TriviaObject) []Question {
+	var matching []Question
+	for _, question := range questions {
+		if trivia.Category != "" && question.Category != "" && !strings.EqualFold(question.Category, trivia.Category) {
+			continue
+		}
+		if trivia.Difficulty != "" && question.Difficulty != "" && !strings.EqualFold(question.Difficulty, trivia.Difficulty) {
+			continue
+		}
+		matching = append(matching, question)
+	}
+
+	return matching
+}
+
 func (quiz *Quiz) readQuestionsFromJSON(jsonFile string) ([]Question, error) {
+	return ReadDeck(jsonFile)
+}
+
+// Reads the questions of a local deck
+func ReadDeck(jsonFile string) ([]Question, error) {
 	file, err := ioutil.ReadFile(jsonFile)
 	if err != nil {
-		fmt.Printf("Failed to read from file %s: %s\n", jsonFile, err.Error())
+		fmt.Fprintf(os.Stderr, "Failed to read from file %s: %s\n", jsonFile, err.Error())
 		return nil, err
 	}
 
@@ -82,24 +217,38 @@ func (quiz *Quiz) readQuestionsFromJSON(jsonFile string) ([]Question, error) {
 
 	err = json.Unmarshal([]byte(file), &data)
 	if err != nil {
-		fmt.Printf("Failed to parse JSON file %s: %s\n", jsonFile, err.Error())
+		fmt.Fprintf(os.Stderr, "Failed to parse JSON file %s: %s\n", jsonFile, err.Error())
 		return nil, err
 	}
 
 	return data, nil
 }
 
+// Reads the questions of all given decks
+func ReadDecks(jsonFiles []string) ([]Question, error) {
+	var questions []Question
+	for _, jsonFile := range jsonFiles {
+		deck, err := ReadDeck(jsonFile)
+		if err != nil {
+			return nil, err
+		}
+		questions = append(questions, deck...)
+	}
+
+	return questions, nil
+}
+
 func (quiz *Quiz) ReadConfigurationFromYAML(yamlFile string) (Configuration, error) {
 	var data Configuration
 	file, err := ioutil.ReadFile(yamlFile)
 	if err != nil {
-		fmt.Printf("Failed to read configuration from file %s: %s\n", yamlFile, err.Error())
+		fmt.Fprintf(os.Stderr, "Failed to read configuration from file %s: %s\n", yamlFile, err.Error())
 		return data, err
 	}
 
 	err = yaml.Unmarshal([]byte(file), &data)
 	if err != nil {
-		fmt.Printf("Failed to parse YAML file %s: %s\n", yamlFile, err.Error())
+		fmt.Fprintf(os.Stderr, "Failed to parse YAML file %s: %s\n", yamlFile, err.Error())
 		return data, err
 	}
 
@@ -111,19 +260,19 @@ func (quiz *Quiz) createTriviaURL(configuration Configuration) (string, error) {
 	amount := configuration.Trivia.Amount
 	if base == "" || amount == "" {
 		err := fmt.Errorf("Mandatory configurations 'base_url' or/and 'amount' missing")
-		fmt.Println("Error:", err.Error())
+		fmt.Fprintln(os.Stderr, "Error:", err.Error())
 		return "", err
 	}
 
 	triviaUrl, err := url.Parse(base)
 	if err != nil {
-		fmt.Println("Error:", err.Error())
+		fmt.Fprintln(os.Stderr, "Error:", err.Error())
 		return "", err
 
 	}
 	if triviaUrl.Scheme == "" || triviaUrl.Host == "" {
 		err := fmt.Errorf("base_url is missing scheme or host")
-		fmt.Println("Error:", err.Error())
+		fmt.Fprintln(os.Stderr, "Error:", err.Error())
 		return "", err
 	}
 	params := url.Values{}
@@ -151,13 +300,13 @@ func (quiz *Quiz) readQuestionsFromURL(url string) ([]Question, error) {
 	var questions []Question
 
 	if err != nil {
-		fmt.Printf("ERROR: %s\n", err.Error())
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err.Error())
 		return questions, err
 	}
 
 	if resp.StatusCode != 200 {
 		err := fmt.Errorf("Http request not OK: %s", resp.Status)
-		fmt.Printf("ERROR: %s\n", err.Error())
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err.Error())
 		return questions, err
 	}
 
@@ -167,17 +316,18 @@ func (quiz *Quiz) readQuestionsFromURL(url string) ([]Question, error) {
 
 	err = json.Unmarshal(body, &openTriviaResponse)
 	if err != nil {
-		fmt.Printf("ERROR: %s\n", err.Error())
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err.Error())
 		return questions, err
 	}
 
 	if len(openTriviaResponse.Results) == 0 {
 		err := fmt.Errorf("Unable to resolve response into question(s): %s", body)
-		fmt.Printf("ERROR: %s\n", err.Error())
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err.Error())
 		return questions, err
 	}
 
 	for i, question := range openTriviaResponse.Results {
+		question.Category = html.UnescapeString(question.Category)
 		question.Question = html.UnescapeString(question.Question)
 		question.RightAnswer = html.UnescapeString(question.RightAnswer)
 		question.WrongAnswers[0] = html.UnescapeString(question.WrongAnswers[0])
@@ -201,38 +351,54 @@ func (quiz *Quiz) GetUserInput(stdin io.Reader) (string, error) {
 }
 
 func (quiz *Quiz) FormatQuestion(question Question, answerMap map[string]string) string {
-	questionAndAnswers := fmt.Sprintf(
-		"\nQuestion: %s\n1: %s\n2: %s\n3: %s\n4: %s\nAnswer: ",
-		question.Question,
-		answerMap["1"],
-		answerMap["2"],
-		answerMap["3"],
-		answerMap["4"])
+	var builder strings.Builder
+	blocks := question.Blocks()
+	separator := " "
+	if len(blocks) > 0 && blocks[0].Type != ContentText {
+		// Code and images start on a line of their own
+		separator = "\n"
+	}
+	builder.WriteString(fmt.Sprintf("\n%s:%s%s\n", quiz.localizer.Text("question"), separator, RenderBlocks(blocks, quiz.color, quiz.localizer)))
+	if quiz.accessible {
+		builder.WriteString(quiz.localizer.Plural("options_count", len(answerMap), len(answerMap)) + "\n")
+	}
+	for _, label := range SortedLabels(answerMap) {
+		if quiz.accessible {
+			builder.WriteString(quiz.localizer.Text("option", label, answerMap[label]) + "\n")
+		} else {
+			builder.WriteString(fmt.Sprintf("%s: %s\n", label, answerMap[label]))
+		}
+	}
+	builder.WriteString(quiz.localizer.Text("answer_prompt") + ": ")
 
-	return questionAndAnswers
+	return builder.String()
 }
 
-func (quiz *Quiz) GetAnswerMap(question Question, randomizeSeed bool) map[string]string {
+func (quiz *Quiz) GetAnswerMap(question Question) map[string]string {
 	var answerOptions []string
 	answerOptions = append(answerOptions, question.WrongAnswers[0])
 	answerOptions = append(answerOptions, question.WrongAnswers[1])
 	answerOptions = append(answerOptions, question.WrongAnswers[2])
 	answerOptions = append(answerOptions, question.RightAnswer)
-	randomizedAnswers := quiz.randomizeAnswers(answerOptions, randomizeSeed)
+	randomizedAnswers := quiz.randomizeAnswers(answerOptions)
 
-	return map[string]string{
-		"1": randomizedAnswers[0],
-		"2": randomizedAnswers[1],
-		"3": randomizedAnswers[2],
-		"4": randomizedAnswers[3],
+	labels := quiz.labels
+	if labels == nil {
+		labels, _ = Labels(LabelsNumbers)
 	}
+	answerMap := map[string]string{}
+	for index, label := range labels {
+		answerMap[label] = randomizedAnswers[index]
+	}
+	return answerMap
 }
 
 // This function verifies that the answer is correct
 func (quiz *Quiz) Verify(question Question, answerMap map[string]string, userInput string) (bool, error) {
-
-	//Assume userInput is 1, 2, 3 or 4
-	userAnswer := answerMap[userInput]
+	userAnswer, found := answerMap[NormalizeChoice(userInput)]
+	if !found {
+		return false, errors.New(quiz.localizer.Text("invalid_answer", userInput))
+	}
 
 	if userAnswer == question.RightAnswer {
 		return true, nil
@@ -244,28 +410,47 @@ func (quiz *Quiz) Verify(question Question, answerMap map[string]string, userInp
 		}
 	}
 
-	return false, fmt.Errorf("The specified answer is invalid answer: %s", userInput)
+	return false, errors.New(quiz.localizer.Text("invalid_answer", userInput))
 }
 
 // Function that format the result printout to console
 func (quiz *Quiz) FormatResult(numberCorrectAnswers int, numberQuestions int) string {
-	resultString := fmt.Sprintf("You got %d of %d correct answers.", numberCorrectAnswers, numberQuestions)
+	return quiz.localizer.Plural("result", numberQuestions, numberCorrectAnswers, numberQuestions)
+}
+
+func (quiz *Quiz) randomizeAnswers(answers []string) []string {
+	shuffledAnswers := answers
+	quiz.shuffle(len(shuffledAnswers), func(i, j int) { shuffledAnswers[i], shuffledAnswers[j] = shuffledAnswers[j], shuffledAnswers[i] })
 
-	return resultString
+	return shuffledAnswers
 }
 
-func (quiz *Quiz) randomizeAnswers(answers []string, randomizeSeed bool) []string {
+// Shuffles with the shuffler and remembers the size of the shuffle
+func (quiz *Quiz) shuffle(n int, swap func(i, j int)) {
+	quiz.draws = append(quiz.draws, n)
+	quiz.getShuffler().Shuffle(n, swap)
+}
 
-	var seed int64 = 0 // Default to 0 for deterministic testing
+// Returns the sizes of the shuffles made so far. A saved game keeps them so
+// that a quiz with the same seed can skip them and shuffle the remaining
+// answers as the original game would have.
+func (quiz *Quiz) Draws() []int {
+	return quiz.draws
+}
 
-	if randomizeSeed {
-		// See https://yourbasic.org/golang/shuffle-slice-array/
-		seed = time.Now().UnixNano()
+// Makes shuffles of the given sizes without using them, to continue from
+// where the quiz that returned them with Draws stopped
+func (quiz *Quiz) SkipDraws(draws []int) {
+	for _, n := range draws {
+		quiz.shuffle(n, func(i, j int) {})
 	}
+}
 
-	shuffledAnswers := answers
-	rand.Seed(seed)
-	rand.Shuffle(len(shuffledAnswers), func(i, j int) { shuffledAnswers[i], shuffledAnswers[j] = shuffledAnswers[j], shuffledAnswers[i] })
-
-	return shuffledAnswers
+// Returns the injected shuffler. A quiz created without one shuffles from
+// seed 0, which keeps it deterministic for testing.
+func (quiz *Quiz) getShuffler() Shuffler {
+	if quiz.shuffler == nil {
+		quiz.shuffler = rand.New(rand.NewSource(0))
+	}
+	return quiz.shuffler
 }
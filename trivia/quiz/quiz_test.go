@@ -58,7 +58,7 @@ func TestGetQuestions(t *testing.T) {
 	}
 
 	var testConfiguration = Configuration{
-		QuestionFile: "questions.json",
+		QuestionFile: "testdata/questions.json",
 		Trivia:       trivia,
 	}
 
@@ -75,15 +75,39 @@ func TestGetQuestionsBadUrl(t *testing.T) {
 	defer func() { testServer.Close() }()
 
 	var testConfiguration = Configuration{
-		QuestionFile: "questions.json",
+		QuestionFile: "testdata/questions.json",
 	}
 
 	questions, _ := quiz.GetQuestions(testConfiguration)
 	assert.Equal(t, 3, len(questions))
 }
 
+func TestGetQuestionsOfflineAmount(t *testing.T) {
+	var testConfiguration = Configuration{
+		QuestionFile: "testdata/questions.json",
+		Trivia:       TriviaObject{Amount: "2"},
+	}
+
+	questions, _ := quiz.GetQuestions(testConfiguration)
+	assert.Equal(t, 2, len(questions))
+}
+
+func TestMatchingQuestions(t *testing.T) {
+	questions := []Question{
+		{Question: "Untagged"},
+		{Question: "Easy science", Category: "Science & Nature", Difficulty: "easy"},
+		{Question: "Hard science", Category: "Science & Nature", Difficulty: "hard"},
+		{Question: "Easy history", Category: "History", Difficulty: "easy"},
+	}
+
+	matching := matchingQuestions(questions, TriviaObject{Category: "science & nature", Difficulty: "easy"})
+
+	assert.Equal(t, []Question{questions[0], questions[1]}, matching)
+	assert.Equal(t, questions, matchingQuestions(questions, TriviaObject{}))
+}
+
 func TestReadQuestionsFromJSON(t *testing.T) {
-	questions, _ := quiz.readQuestionsFromJSON("questions.json")
+	questions, _ := quiz.readQuestionsFromJSON("testdata/questions.json")
 	assert.Equal(t, "What is blue and yellow together? (using watercolors)", questions[0].Question)
 	assert.Equal(t, "Green", questions[0].RightAnswer)
 	assert.Equal(t, "Red", questions[0].WrongAnswers[0])
@@ -91,7 +115,7 @@ func TestReadQuestionsFromJSON(t *testing.T) {
 }
 
 func TestReadConfigurationFromYAML(t *testing.T) {
-	configuration, _ := quiz.ReadConfigurationFromYAML("config.yaml")
+	configuration, _ := quiz.ReadConfigurationFromYAML("testdata/config.yaml")
 	assert.NotEmpty(t, configuration.QuestionFile)
 	assert.NotEmpty(t, configuration.Trivia.BaseURL)
 	assert.NotEmpty(t, configuration.Trivia.Amount)
@@ -105,7 +129,7 @@ func TestCreateTriviaURL(t *testing.T) {
 		Difficulty: "s",
 	}
 	var testConfiguration = Configuration{
-		QuestionFile: "questions.json",
+		QuestionFile: "testdata/questions.json",
 		Trivia:       trivia,
 	}
 
@@ -118,7 +142,7 @@ func TestCreateTriviaURLMissingMandatory(t *testing.T) {
 		BaseURL: "trivia.com/api",
 	}
 	var testConfiguration = Configuration{
-		QuestionFile: "questions.json",
+		QuestionFile: "testdata/questions.json",
 		Trivia:       trivia,
 	}
 
@@ -133,7 +157,7 @@ func TestCreateTriviaURLMissingHost(t *testing.T) {
 		Amount:  "10",
 	}
 	var testConfiguration = Configuration{
-		QuestionFile: "questions.json",
+		QuestionFile: "testdata/questions.json",
 		Trivia:       trivia,
 	}
 
@@ -147,7 +171,7 @@ func TestCreateTriviaURLMissingSchema(t *testing.T) {
 		Amount:  "10",
 	}
 	var testConfiguration = Configuration{
-		QuestionFile: "questions.json",
+		QuestionFile: "testdata/questions.json",
 		Trivia:       trivia,
 	}
 
@@ -244,11 +268,57 @@ func TestGetUserInputError(t *testing.T) {
 func TestRandomizeAnswers(t *testing.T) {
 	var answers = []string{"A", "B", "C"}
 	var expected = []string{"B", "A", "C"}
-	var randomizeAnswers bool = false
-	actual := quiz.randomizeAnswers(answers, randomizeAnswers)
+	actual := NewQuiz(0).randomizeAnswers(answers)
 	assert.Equal(t, expected, actual)
 }
 
+func TestRandomizeAnswersIsReproducible(t *testing.T) {
+	first := NewQuiz(42)
+	second := NewQuiz(42)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, first.GetAnswerMap(testQuestion), second.GetAnswerMap(testQuestion))
+	}
+}
+
+func TestSkipDraws(t *testing.T) {
+	original := NewQuiz(42)
+	original.GetAnswerMap(testQuestion)
+	original.GetAnswerMap(testQuestion)
+	resumed := NewQuiz(42)
+
+	resumed.SkipDraws(original.Draws())
+
+	assert.Equal(t, []int{4, 4}, resumed.Draws())
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, original.GetAnswerMap(testQuestion), resumed.GetAnswerMap(testQuestion))
+	}
+}
+
+// Shuffler that reverses the elements
+type reverseShuffler struct{}
+
+func (reverseShuffler) Shuffle(n int, swap func(i, j int)) {
+	for i := 0; i < n/2; i++ {
+		swap(i, n-1-i)
+	}
+}
+
+func TestNewQuizWithShuffler(t *testing.T) {
+	quiz := NewQuizWithShuffler(reverseShuffler{})
+	answerMap := quiz.GetAnswerMap(testQuestion)
+	assert.Equal(t, map[string]string{"1": "Go", "2": "Ruby", "3": "Java", "4": "Python"}, answerMap)
+}
+
+func TestGetQuestionsShuffled(t *testing.T) {
+	quiz := NewQuizWithShuffler(reverseShuffler{})
+	configuration := Configuration{QuestionFile: "testdata/questions.json", ShuffleQuestions: true}
+
+	questions, err := quiz.GetQuestions(configuration)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(questions))
+	assert.Equal(t, "What is blue and yellow together? (using watercolors)", questions[2].Question)
+}
+
 func TestFormatQuestion(t *testing.T) {
 	actualQandA := quiz.FormatQuestion(testQuestion, testAnswerMap)
 	expectedQandA := "\nQuestion: Which language is this written in?\n" +
@@ -261,9 +331,8 @@ func TestFormatQuestion(t *testing.T) {
 }
 
 func TestGetAnswerMap(t *testing.T) {
-	var randomizeAnswers bool = false
 	question := testQuestion
-	actual := quiz.GetAnswerMap(question, randomizeAnswers)
+	actual := NewQuiz(0).GetAnswerMap(question)
 	assert.Equal(t, testAnswerMap, actual)
 }
 
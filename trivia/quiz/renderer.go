@@ -0,0 +1,342 @@
+package quiz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// https://golangbyexample.com/print-output-text-color-console/
+const colorReset string = "\033[0m"
+const colorRed string = "\033[31m"
+const colorGreen string = "\033[32m"
+
+// Names of the renderers for NewRendererByName
+const RendererAuto string = "auto"
+const RendererColor string = "color"
+const RendererNoColor string = "nocolor"
+const RendererPlain string = "plain"
+const RendererJSON string = "jsonl"
+
+// A question shown to the players
+type QuestionEvent struct {
+	Number          int               `json:"number"`
+	NumberQuestions int               `json:"number_questions"`
+	Category        string            `json:"category,omitempty"`
+	Difficulty      string            `json:"difficulty,omitempty"`
+	Question        string            `json:"question"`
+	Content         []ContentBlock    `json:"content,omitempty"`
+	Options         map[string]string `json:"options"`
+	// The question as formatted by the quiz
+	Text string `json:"-"`
+}
+
+// The verdict on one answer
+type VerdictEvent struct {
+	Player      string `json:"player,omitempty"`
+	Team        string `json:"team,omitempty"`
+	Question    string `json:"question"`
+	Choice      string `json:"choice"`
+	Answer      string `json:"answer"`
+	RightAnswer string `json:"right_answer"`
+	Correct     bool   `json:"correct"`
+	Skipped     bool   `json:"skipped,omitempty"`
+	TimedOut    bool   `json:"timed_out,omitempty"`
+	Explanation string `json:"explanation,omitempty"`
+	Source      string `json:"source,omitempty"`
+	// The question as blocks when it has code or images
+	Content []ContentBlock `json:"content,omitempty"`
+}
+
+// The right answer revealed after several players or teams have answered
+type RevealEvent struct {
+	Question    string         `json:"question"`
+	RightAnswer string         `json:"right_answer"`
+	Explanation string         `json:"explanation,omitempty"`
+	Source      string         `json:"source,omitempty"`
+	Answers     []VerdictEvent `json:"answers"`
+}
+
+// A score during the game, e.g. at the end of a round
+type ScoreEvent struct {
+	Title           string        `json:"title,omitempty"`
+	CorrectAnswers  int           `json:"correct_answers"`
+	NumberQuestions int           `json:"number_questions"`
+	Scores          []PlayerScore `json:"scores,omitempty"`
+	// The score as formatted by the quiz
+	Text string `json:"-"`
+}
+
+// The final result, with the ranking when several players or teams played
+// and the questions to review when one player did
+type GameOverEvent struct {
+	CorrectAnswers  int            `json:"correct_answers"`
+	NumberQuestions int            `json:"number_questions"`
+	Scores          []PlayerScore  `json:"scores,omitempty"`
+	Review          []VerdictEvent `json:"review,omitempty"`
+	// The result as formatted by the quiz
+	Text string `json:"-"`
+}
+
+// Presents the events of a game. Message is for everything else the game has
+// to say, like whose turn it is or why an answer was not accepted.
+type Renderer interface {
+	QuestionShown(event QuestionEvent)
+	AnswerVerdict(event VerdictEvent)
+	AnswersRevealed(event RevealEvent)
+	Score(event ScoreEvent)
+	GameOver(event GameOverEvent)
+	Message(text string)
+}
+
+// Returns the renderer suiting the output: plain text when it is not a
+// terminal, text without color when NO_COLOR is set, otherwise color
+func NewRenderer(out *os.File, localizer *Localizer) Renderer {
+	return selectRenderer(out, localizer, isTerminal(out), os.Getenv("NO_COLOR"))
+}
+
+func selectRenderer(out io.Writer, localizer *Localizer, terminal bool, noColor string) Renderer {
+	if !terminal {
+		return NewPlainRenderer(out, localizer)
+	}
+	if noColor != "" {
+		return NewNoColorRenderer(out, localizer)
+	}
+	return NewColorRenderer(out, localizer)
+}
+
+// Returns the renderer with the given name, or the one suiting the output for
+// RendererAuto
+func NewRendererByName(name string, out *os.File, localizer *Localizer) (Renderer, error) {
+	switch name {
+	case RendererAuto, "":
+		return NewRenderer(out, localizer), nil
+	case RendererColor:
+		return NewColorRenderer(out, localizer), nil
+	case RendererNoColor:
+		return NewNoColorRenderer(out, localizer), nil
+	case RendererPlain:
+		return NewPlainRenderer(out, localizer), nil
+	case RendererAccessible:
+		return NewAccessibleRenderer(out, localizer), nil
+	case RendererJSON:
+		return NewJSONRenderer(out), nil
+	}
+	return nil, fmt.Errorf("Unknown output '%s', use %s, %s, %s, %s, %s or %s",
+		name, RendererAuto, RendererColor, RendererNoColor, RendererPlain, RendererAccessible, RendererJSON)
+}
+
+// Tells whether the renderer writes color, so that the quiz highlights the
+// code of its questions to match
+func UsesColor(renderer Renderer) bool {
+	text, ok := renderer.(*textRenderer)
+	return ok && text.color
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Writes the game as text in the language of the localizer, marking the
+// verdict words with the given formats and highlighting code when color is
+// set. Accessible text spells out verdicts and question numbers.
+type textRenderer struct {
+	out        io.Writer
+	localizer  *Localizer
+	correct    string
+	wrong      string
+	color      bool
+	accessible bool
+}
+
+// Text with correct answers in green and wrong ones in red
+func NewColorRenderer(out io.Writer, localizer *Localizer) Renderer {
+	return &textRenderer{out: out, localizer: localizer, correct: colorGreen + "%s" + colorReset, wrong: colorRed + "%s" + colorReset, color: true}
+}
+
+// Text for terminals without color, marking verdicts with symbols
+func NewNoColorRenderer(out io.Writer, localizer *Localizer) Renderer {
+	return &textRenderer{out: out, localizer: localizer, correct: "✔ %s", wrong: "✘ %s"}
+}
+
+// Plain text, e.g. for logs and pipes
+func NewPlainRenderer(out io.Writer, localizer *Localizer) Renderer {
+	return &textRenderer{out: out, localizer: localizer, correct: "%s", wrong: "%s"}
+}
+
+func (renderer *textRenderer) verdict(correct bool) string {
+	if renderer.accessible {
+		if correct {
+			return renderer.localizer.Text("accessible_correct")
+		}
+		return renderer.localizer.Text("accessible_incorrect")
+	}
+	if correct {
+		return fmt.Sprintf(renderer.correct, renderer.localizer.Text("correct"))
+	}
+	return fmt.Sprintf(renderer.wrong, renderer.localizer.Text("wrong"))
+}
+
+func (renderer *textRenderer) QuestionShown(event QuestionEvent) {
+	if renderer.accessible {
+		fmt.Fprint(renderer.out, renderer.localizer.Text("accessible_question", event.Number, event.NumberQuestions))
+	} else {
+		fmt.Fprintf(renderer.out, "%d/%d", event.Number, event.NumberQuestions)
+	}
+	fmt.Fprintln(renderer.out, event.Text)
+}
+
+func (renderer *textRenderer) AnswerVerdict(event VerdictEvent) {
+	var builder strings.Builder
+	if renderer.accessible && !event.Skipped && !event.TimedOut {
+		// The verdict comes first, so that it is heard first
+		if event.Correct {
+			builder.WriteString(renderer.verdict(true) + ".\n")
+		} else {
+			builder.WriteString(renderer.localizer.Text("accessible_verdict_wrong", renderer.verdict(false), event.RightAnswer) + "\n")
+		}
+	} else if event.Correct {
+		builder.WriteString(renderer.localizer.Text("verdict_correct", renderer.verdict(true)) + "\n")
+	} else if event.Skipped {
+		builder.WriteString(renderer.localizer.Text("verdict_skipped", event.RightAnswer) + "\n")
+	} else if event.TimedOut {
+		builder.WriteString(renderer.localizer.Text("verdict_timed_out", event.RightAnswer) + "\n")
+	} else {
+		builder.WriteString(renderer.localizer.Text("verdict_wrong", renderer.verdict(false), event.RightAnswer) + "\n")
+	}
+	renderer.explain(&builder, "", event.Explanation, event.Source)
+	fmt.Fprintln(renderer.out, builder.String())
+}
+
+// Writes the explanation and the source of an answer, when there are any
+func (renderer *textRenderer) explain(builder *strings.Builder, indent string, explanation string, source string) {
+	if explanation != "" {
+		builder.WriteString(indent + explanation + "\n")
+	}
+	if source != "" {
+		builder.WriteString(indent + renderer.localizer.Text("source", source) + "\n")
+	}
+}
+
+func (renderer *textRenderer) AnswersRevealed(event RevealEvent) {
+	var builder strings.Builder
+	builder.WriteString(renderer.localizer.Text("reveal", event.RightAnswer) + "\n")
+	renderer.explain(&builder, "", event.Explanation, event.Source)
+	for _, answer := range event.Answers {
+		if answer.Team != "" {
+			builder.WriteString(fmt.Sprintf("  %s: %s (%s)\n", answer.Team, answer.Answer, renderer.verdict(answer.Correct)))
+		} else {
+			builder.WriteString(fmt.Sprintf("  %s: %s\n", answer.Player, renderer.verdict(answer.Correct)))
+		}
+	}
+	fmt.Fprintln(renderer.out, builder.String())
+}
+
+func (renderer *textRenderer) Score(event ScoreEvent) {
+	fmt.Fprintln(renderer.out, event.Text)
+}
+
+func (renderer *textRenderer) GameOver(event GameOverEvent) {
+	fmt.Fprintln(renderer.out, event.Text)
+	if len(event.Review) == 0 {
+		return
+	}
+
+	var builder strings.Builder
+	builder.WriteString("\n" + renderer.localizer.Text("review") + "\n")
+	for _, missed := range event.Review {
+		question := missed.Question
+		if len(missed.Content) > 0 {
+			question = RenderBlocks(missed.Content, renderer.color, renderer.localizer)
+		}
+		builder.WriteString(fmt.Sprintf("%s %s\n", fmt.Sprintf(renderer.wrong, "-"), question))
+		builder.WriteString("  " + renderer.localizer.Text("reveal", missed.RightAnswer) + "\n")
+		renderer.explain(&builder, "  ", missed.Explanation, missed.Source)
+	}
+	fmt.Fprint(renderer.out, builder.String())
+}
+
+func (renderer *textRenderer) Message(text string) {
+	fmt.Fprint(renderer.out, text)
+}
+
+// Writes every event as a JSON object on a line of its own, with its type in
+// the field "event" and, in the protocol, the protocol version
+type jsonRenderer struct {
+	encoder *json.Encoder
+	version int
+}
+
+type jsonHeader struct {
+	Event   string `json:"event"`
+	Version int    `json:"version,omitempty"`
+}
+
+func NewJSONRenderer(out io.Writer) Renderer {
+	return &jsonRenderer{encoder: json.NewEncoder(out)}
+}
+
+func (renderer *jsonRenderer) header(event string) jsonHeader {
+	return jsonHeader{Event: event, Version: renderer.version}
+}
+
+func (renderer *jsonRenderer) QuestionShown(event QuestionEvent) {
+	renderer.encoder.Encode(struct {
+		jsonHeader
+		QuestionEvent
+	}{renderer.header("question"), event})
+}
+
+func (renderer *jsonRenderer) AnswerVerdict(event VerdictEvent) {
+	renderer.encoder.Encode(struct {
+		jsonHeader
+		VerdictEvent
+	}{renderer.header("verdict"), event})
+}
+
+func (renderer *jsonRenderer) AnswersRevealed(event RevealEvent) {
+	renderer.encoder.Encode(struct {
+		jsonHeader
+		RevealEvent
+	}{renderer.header("reveal"), event})
+}
+
+func (renderer *jsonRenderer) Score(event ScoreEvent) {
+	renderer.encoder.Encode(struct {
+		jsonHeader
+		ScoreEvent
+	}{renderer.header("score"), event})
+}
+
+func (renderer *jsonRenderer) GameOver(event GameOverEvent) {
+	renderer.encoder.Encode(struct {
+		jsonHeader
+		GameOverEvent
+	}{renderer.header("game_over"), event})
+}
+
+// Skips text that is only whitespace, like the blank lines between questions
+func (renderer *jsonRenderer) Message(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	renderer.encoder.Encode(struct {
+		jsonHeader
+		Text string `json:"text"`
+	}{renderer.header("message"), text})
+}
+
+// Reports a problem that does not end the game, like input that could not be read
+func (renderer *jsonRenderer) error(message string) {
+	renderer.encoder.Encode(struct {
+		jsonHeader
+		Message string `json:"message"`
+	}{renderer.header("error"), message})
+}
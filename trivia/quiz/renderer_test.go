@@ -0,0 +1,120 @@
+package quiz
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var wrongVerdict = VerdictEvent{Question: "Which color has the sky?", Choice: "1", Answer: "Red", RightAnswer: "Blue"}
+
+func TestSelectRenderer(t *testing.T) {
+	var out bytes.Buffer
+
+	assert.Equal(t, NewPlainRenderer(&out, nil), selectRenderer(&out, nil, false, ""))
+	assert.Equal(t, NewPlainRenderer(&out, nil), selectRenderer(&out, nil, false, "1"))
+	assert.Equal(t, NewNoColorRenderer(&out, nil), selectRenderer(&out, nil, true, "1"))
+	assert.Equal(t, NewColorRenderer(&out, nil), selectRenderer(&out, nil, true, ""))
+}
+
+func TestNewRendererByName_Unknown(t *testing.T) {
+	_, err := NewRendererByName("html", nil, nil)
+
+	assert.EqualError(t, err, "Unknown output 'html', use auto, color, nocolor, plain, accessible or jsonl")
+}
+
+func TestColorRenderer_AnswerVerdict(t *testing.T) {
+	var out bytes.Buffer
+
+	NewColorRenderer(&out, nil).AnswerVerdict(wrongVerdict)
+
+	assert.Equal(t, "Your answer is "+colorRed+"wrong"+colorReset+". The correct answer is 'Blue'\n\n", out.String())
+}
+
+func TestNoColorRenderer_AnswerVerdict(t *testing.T) {
+	var out bytes.Buffer
+
+	NewNoColorRenderer(&out, nil).AnswerVerdict(VerdictEvent{Correct: true})
+
+	assert.Equal(t, "Your answer is ✔ correct.\n\n", out.String())
+}
+
+func TestPlainRenderer(t *testing.T) {
+	var out bytes.Buffer
+	renderer := NewPlainRenderer(&out, nil)
+
+	renderer.QuestionShown(QuestionEvent{Number: 1, NumberQuestions: 2, Text: "\nQuestion: Which color has the sky?"})
+	renderer.AnswerVerdict(wrongVerdict)
+	renderer.AnswersRevealed(RevealEvent{RightAnswer: "Blue", Answers: []VerdictEvent{
+		{Player: "anna", Correct: true},
+		{Team: "red", Answer: "Red"},
+	}})
+	renderer.GameOver(GameOverEvent{Text: "You got 0 of 1 correct answers."})
+
+	assert.Equal(t, "1/2\nQuestion: Which color has the sky?\n"+
+		"Your answer is wrong. The correct answer is 'Blue'\n\n"+
+		"The correct answer is 'Blue'\n  anna: correct\n  red: Red (wrong)\n\n"+
+		"You got 0 of 1 correct answers.\n", out.String())
+	assert.NotContains(t, out.String(), "\033")
+}
+
+func TestPlainRenderer_Explanation(t *testing.T) {
+	var out bytes.Buffer
+	renderer := NewPlainRenderer(&out, nil)
+	explained := wrongVerdict
+	explained.Explanation = "Blue light scatters the most."
+	explained.Source = "https://en.wikipedia.org/wiki/Rayleigh_scattering"
+
+	renderer.AnswerVerdict(explained)
+	renderer.GameOver(GameOverEvent{Text: "You got 0 of 1 correct answers.", Review: []VerdictEvent{explained}})
+
+	assert.Equal(t, "Your answer is wrong. The correct answer is 'Blue'\n"+
+		"Blue light scatters the most.\nSource: https://en.wikipedia.org/wiki/Rayleigh_scattering\n\n"+
+		"You got 0 of 1 correct answers.\n\n"+
+		"Questions to review:\n- Which color has the sky?\n  The correct answer is 'Blue'\n"+
+		"  Blue light scatters the most.\n  Source: https://en.wikipedia.org/wiki/Rayleigh_scattering\n", out.String())
+}
+
+func TestPlainRenderer_ReviewContent(t *testing.T) {
+	var out bytes.Buffer
+	missed := VerdictEvent{Question: codeQuestion.Question, RightAnswer: "6", Content: codeQuestion.RichContent()}
+
+	NewPlainRenderer(&out, nil).GameOver(GameOverEvent{Text: "You got 0 of 1 correct answers.", Review: []VerdictEvent{missed}})
+
+	assert.Equal(t, "You got 0 of 1 correct answers.\n\n"+
+		"Questions to review:\n- What does this program print?\n    fmt.Println(len(\"héllo\"))\n"+
+		"Image: The Go gopher (https://go.dev/images/gopher.png)\n  The correct answer is '6'\n", out.String())
+}
+
+func TestUsesColor(t *testing.T) {
+	assert.True(t, UsesColor(NewColorRenderer(nil, nil)))
+	assert.False(t, UsesColor(NewPlainRenderer(nil, nil)))
+	assert.False(t, UsesColor(NewJSONRenderer(nil)))
+}
+
+func TestJSONRenderer(t *testing.T) {
+	var out bytes.Buffer
+	renderer := NewJSONRenderer(&out)
+
+	renderer.QuestionShown(QuestionEvent{Number: 1, NumberQuestions: 1, Question: "Which color has the sky?",
+		Options: map[string]string{"1": "Red", "2": "Blue"}, Text: "formatted"})
+	renderer.Message("anna: ")
+	renderer.Message("\n")
+	renderer.AnswerVerdict(wrongVerdict)
+	renderer.GameOver(GameOverEvent{NumberQuestions: 1, Scores: []PlayerScore{{Rank: 1, Name: "anna", NumberQuestions: 1}}})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, 4, len(lines))
+	assert.Equal(t, `{"event":"question","number":1,"number_questions":1,"question":"Which color has the sky?","options":{"1":"Red","2":"Blue"}}`, lines[0])
+	assert.Equal(t, `{"event":"message","text":"anna:"}`, lines[1])
+	assert.Equal(t, `{"event":"verdict","question":"Which color has the sky?","choice":"1","answer":"Red","right_answer":"Blue","correct":false}`, lines[2])
+
+	var gameOver map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[3]), &gameOver))
+	assert.Equal(t, "game_over", gameOver["event"])
+	assert.Equal(t, float64(1), gameOver["number_questions"])
+	assert.Len(t, gameOver["scores"], 1)
+}
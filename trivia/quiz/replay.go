@@ -0,0 +1,351 @@
+package quiz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const recordingVersion int = 1
+
+// The outcome of one call to Verify
+type Verdict struct {
+	Question string `json:"question"`
+	Input    string `json:"input"`
+	Correct  bool   `json:"correct"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Everything needed to play a session again: the options it was started
+// with, the language and answer labels, the configuration, the questions of
+// every GetQuestions call, the answer maps, the typed input, the time-outs
+// and the verdicts
+type Recording struct {
+	Version   int           `json:"version"`
+	Created   time.Time     `json:"created"`
+	Player    string        `json:"player,omitempty"`
+	Players   []string      `json:"players,omitempty"`
+	HotSeat   string        `json:"hotseat,omitempty"`
+	Teams     []Team        `json:"teams,omitempty"`
+	Consensus string        `json:"consensus,omitempty"`
+	Seed      int64         `json:"seed,omitempty"`
+	TimeLimit time.Duration `json:"time_limit,omitempty"`
+	// The commands typed depend on the language, the answers on the labels
+	Language      string              `json:"language,omitempty"`
+	Labels        string              `json:"labels,omitempty"`
+	Configuration Configuration       `json:"configuration"`
+	Questions     [][]Question        `json:"questions"`
+	AnswerMaps    []map[string]string `json:"answer_maps"`
+	Inputs        []string            `json:"inputs"`
+	// For every question the time was up for, the number of inputs typed
+	// before it; what was being typed answers the next question
+	TimedOut []int     `json:"timed_out,omitempty"`
+	Verdicts []Verdict `json:"verdicts"`
+	// Error that ended the session, empty when it was played to the end
+	Error string `json:"error,omitempty"`
+}
+
+// Wraps a quiz and records everything the game asks it for
+type Recorder struct {
+	QuizInterface
+	// Input is read while the game waits for the time to be up
+	mutex     sync.Mutex
+	recording Recording
+	// Number of inputs typed before the one being read
+	reading int
+}
+
+func NewRecorder(quiz QuizInterface, options Options) *Recorder {
+	return &Recorder{
+		QuizInterface: quiz,
+		recording: Recording{
+			Version:   recordingVersion,
+			Created:   time.Now(),
+			Player:    options.Player,
+			Players:   options.Players,
+			HotSeat:   options.HotSeat,
+			Teams:     options.Teams,
+			Consensus: options.Consensus,
+			Seed:      options.Seed,
+			TimeLimit: options.TimeLimit,
+			Language:  options.Localizer.Language(),
+		},
+	}
+}
+
+// Tells the shuffles of the recorded quiz, so that a recorded game that is
+// saved is resumed with the same answer orders
+func (recorder *Recorder) Draws() []int {
+	if counter, ok := recorder.QuizInterface.(drawCounter); ok {
+		return counter.Draws()
+	}
+	return nil
+}
+
+// Lets the recorded quiz highlight code when the game is written with color
+func (recorder *Recorder) SetColor(color bool) {
+	if setter, ok := recorder.QuizInterface.(colorSetter); ok {
+		setter.SetColor(color)
+	}
+}
+
+// Records the label scheme the quiz was given with SetLabels
+func (recorder *Recorder) SetLabels(scheme string) {
+	recorder.recording.Labels = scheme
+}
+
+func (recorder *Recorder) ReadConfigurationFromYAML(yamlFile string) (Configuration, error) {
+	configuration, err := recorder.QuizInterface.ReadConfigurationFromYAML(yamlFile)
+	recorder.recording.Configuration = configuration
+	return configuration, err
+}
+
+func (recorder *Recorder) GetQuestions(configuration Configuration) ([]Question, error) {
+	questions, err := recorder.QuizInterface.GetQuestions(configuration)
+	if err == nil {
+		recorder.recording.Questions = append(recorder.recording.Questions, questions)
+	}
+	return questions, err
+}
+
+func (recorder *Recorder) GetAnswerMap(question Question) map[string]string {
+	answerMap := recorder.QuizInterface.GetAnswerMap(question)
+	recorder.recording.AnswerMaps = append(recorder.recording.AnswerMaps, answerMap)
+	return answerMap
+}
+
+func (recorder *Recorder) GetUserInput(stdin io.Reader) (string, error) {
+	recorder.mutex.Lock()
+	recorder.reading = len(recorder.recording.Inputs)
+	recorder.mutex.Unlock()
+
+	input, err := recorder.QuizInterface.GetUserInput(stdin)
+	if err == nil {
+		recorder.mutex.Lock()
+		recorder.recording.Inputs = append(recorder.recording.Inputs, input)
+		recorder.mutex.Unlock()
+	}
+	return input, err
+}
+
+// Records that the time was up while an input was being typed, even when it
+// came in just as the time was up
+func (recorder *Recorder) TimeUp() {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recorder.recording.TimedOut = append(recorder.recording.TimedOut, recorder.reading)
+}
+
+func (recorder *Recorder) Verify(question Question, answerMap map[string]string, userInput string) (bool, error) {
+	correct, err := recorder.QuizInterface.Verify(question, answerMap, userInput)
+	verdict := Verdict{Question: question.Question, Input: userInput, Correct: correct}
+	if err != nil {
+		verdict.Error = err.Error()
+	}
+	recorder.recording.Verdicts = append(recorder.recording.Verdicts, verdict)
+	return correct, err
+}
+
+// Returns the recording, including the error the game ended with
+func (recorder *Recorder) Recording(err error) Recording {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recording := recorder.recording
+	if err != nil {
+		recording.Error = err.Error()
+	}
+	return recording
+}
+
+func WriteRecording(recordingFile string, recording Recording) error {
+	data, err := json.MarshalIndent(recording, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(recordingFile, data)
+}
+
+func ReadRecording(recordingFile string) (Recording, error) {
+	var recording Recording
+	file, err := ioutil.ReadFile(recordingFile)
+	if err != nil {
+		return recording, err
+	}
+
+	err = json.Unmarshal(file, &recording)
+	if err != nil {
+		return recording, fmt.Errorf("Failed to parse recording %s: %s", recordingFile, err.Error())
+	}
+	if recording.Version != recordingVersion {
+		return recording, fmt.Errorf("Unsupported recording version %d in %s", recording.Version, recordingFile)
+	}
+
+	return recording, nil
+}
+
+// Plays a recording back: configuration, questions, answer maps and input
+// come from the recording while verification is done by the given quiz, so
+// that changes in verifying answers show up as divergences
+type replayer struct {
+	QuizInterface
+	recording   Recording
+	questions   int
+	answerMaps  int
+	inputs      int
+	timeOuts    int
+	verdicts    int
+	divergences []string
+	// Shows each replayed input where the player typed it
+	renderer Renderer
+}
+
+func (replayer *replayer) diverge(format string, args ...interface{}) {
+	replayer.divergences = append(replayer.divergences, fmt.Sprintf(format, args...))
+}
+
+func (replayer *replayer) ReadConfigurationFromYAML(yamlFile string) (Configuration, error) {
+	return replayer.recording.Configuration, nil
+}
+
+func (replayer *replayer) GetQuestions(configuration Configuration) ([]Question, error) {
+	if replayer.questions >= len(replayer.recording.Questions) {
+		replayer.diverge("The replay asked for more questions than were recorded")
+		return nil, fmt.Errorf("No more recorded questions")
+	}
+	questions := replayer.recording.Questions[replayer.questions]
+	replayer.questions++
+	return questions, nil
+}
+
+func (replayer *replayer) GetAnswerMap(question Question) map[string]string {
+	if replayer.answerMaps >= len(replayer.recording.AnswerMaps) {
+		replayer.diverge("The replay showed more questions than were recorded")
+		return replayer.QuizInterface.GetAnswerMap(question)
+	}
+	answerMap := replayer.recording.AnswerMaps[replayer.answerMaps]
+	replayer.answerMaps++
+	return answerMap
+}
+
+func (replayer *replayer) GetUserInput(stdin io.Reader) (string, error) {
+	// The time is up again where it was up in the recording
+	timedOut := replayer.recording.TimedOut
+	if replayer.timeOuts < len(timedOut) && timedOut[replayer.timeOuts] <= replayer.inputs {
+		replayer.timeOuts++
+		return "", ErrTimeUp
+	}
+	if replayer.inputs >= len(replayer.recording.Inputs) {
+		if replayer.recording.Error == "" {
+			replayer.diverge("The replay asked for more answers than were recorded")
+		}
+		return "", io.EOF
+	}
+	input := replayer.recording.Inputs[replayer.inputs]
+	replayer.inputs++
+	replayer.renderer.Message(input + "\n")
+	return input, nil
+}
+
+func (replayer *replayer) Verify(question Question, answerMap map[string]string, userInput string) (bool, error) {
+	correct, err := replayer.QuizInterface.Verify(question, answerMap, userInput)
+
+	number := replayer.verdicts + 1
+	replayer.verdicts++
+	if number > len(replayer.recording.Verdicts) {
+		replayer.diverge("Verification %d of '%s' was not recorded", number, userInput)
+		return correct, err
+	}
+
+	recorded := replayer.recording.Verdicts[number-1]
+	if recorded.Question != question.Question || recorded.Input != userInput {
+		replayer.diverge("Verification %d: recorded answer '%s' to '%s', replayed answer '%s' to '%s'",
+			number, recorded.Input, recorded.Question, userInput, question.Question)
+	} else if (recorded.Error == "") != (err == nil) || recorded.Correct != correct {
+		replayer.diverge("Verification %d of answer '%s' to '%s': recorded %s, replayed %s",
+			number, userInput, question.Question, formatVerdict(recorded.Correct, recorded.Error), formatVerdict(correct, errorText(err)))
+	}
+
+	return correct, err
+}
+
+func formatVerdict(correct bool, err string) string {
+	if err != "" {
+		return "invalid (" + err + ")"
+	}
+	if correct {
+		return "correct"
+	}
+	return "wrong"
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// Runs the game again against the recording, in its language and with its
+// labels, verifying the recorded answers with the given quiz, and returns
+// every difference from the recording
+func Replay(quiz *Quiz, recording Recording) ([]string, error) {
+	language := recording.Language
+	if language == "" {
+		language = DefaultLanguage
+	}
+	localizer, err := NewLocalizer(language)
+	if err != nil {
+		return nil, err
+	}
+	quiz.SetLocalizer(localizer)
+	err = quiz.SetLabels(recording.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	renderer := NewRenderer(os.Stdout, localizer)
+	replay := &replayer{QuizInterface: quiz, recording: recording, renderer: renderer}
+	err = RunWithOptions(replay, nil, Options{
+		Renderer:  renderer,
+		Player:    recording.Player,
+		Players:   recording.Players,
+		HotSeat:   recording.HotSeat,
+		Teams:     recording.Teams,
+		Consensus: recording.Consensus,
+		Localizer: localizer,
+		TimeLimit: recording.TimeLimit,
+	})
+
+	if errorText(err) != recording.Error {
+		replay.diverge("The recorded session ended with '%s', the replay with '%s'", recording.Error, errorText(err))
+	}
+	if replay.inputs < len(recording.Inputs) {
+		replay.diverge("%d recorded answers were not used", len(recording.Inputs)-replay.inputs)
+	}
+	if replay.timeOuts < len(recording.TimedOut) {
+		replay.diverge("%d recorded time-outs did not happen", len(recording.TimedOut)-replay.timeOuts)
+	}
+	if replay.verdicts < len(recording.Verdicts) {
+		replay.diverge("%d recorded verifications did not happen", len(recording.Verdicts)-replay.verdicts)
+	}
+
+	return replay.divergences, nil
+}
+
+func FormatDivergences(divergences []string, recording Recording) string {
+	if len(divergences) == 0 {
+		return fmt.Sprintf("The replay matches the recording: %d answers verified the same way.", len(recording.Verdicts))
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("The replay differs from the recording in %d places:\n", len(divergences)))
+	for _, divergence := range divergences {
+		builder.WriteString(fmt.Sprintf("  %s\n", divergence))
+	}
+	return builder.String()
+}
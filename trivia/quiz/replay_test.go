@@ -0,0 +1,177 @@
+package quiz
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func recordSession(t *testing.T, inputs ...string) Recording {
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion, testQuestion2}, nil)
+	quizMock.On("GetAnswerMap", testQuestion).Return(testAnswerMap)
+	quizMock.On("GetAnswerMap", testQuestion2).Return(testAnswerMap2)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("FormatResult", mock.Anything, mock.Anything).Return("Formatted result")
+	for _, input := range inputs {
+		quizMock.On("GetUserInput", mock.Anything).Return(input, nil).Once()
+	}
+	quizMock.On("GetUserInput", mock.Anything).Return("", io.EOF)
+	quizMock.On("Verify", mock.Anything, mock.Anything, "4").Return(true, nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, "1").Return(false, nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, "9").Return(false, fmt.Errorf("The specified answer is invalid answer: 9"))
+
+	recorder := NewRecorder(quizMock, Options{Player: "anna", Seed: 3})
+	var stdin bytes.Buffer
+	err := RunWithOptions(recorder, &stdin, Options{Player: "anna"})
+	return recorder.Recording(err)
+}
+
+func TestRecorder(t *testing.T) {
+	recording := recordSession(t, "9", "4", "1")
+
+	assert.Equal(t, recordingVersion, recording.Version)
+	assert.Equal(t, "anna", recording.Player)
+	assert.Equal(t, int64(3), recording.Seed)
+	assert.Equal(t, testConfiguration, recording.Configuration)
+	assert.Equal(t, [][]Question{{testQuestion, testQuestion2}}, recording.Questions)
+	assert.Equal(t, []map[string]string{testAnswerMap, testAnswerMap2}, recording.AnswerMaps)
+	assert.Equal(t, []string{"9", "4", "1"}, recording.Inputs)
+	assert.Equal(t, []Verdict{
+		{Question: testQuestion.Question, Input: "9", Error: "The specified answer is invalid answer: 9"},
+		{Question: testQuestion.Question, Input: "4", Correct: true},
+		{Question: testQuestion2.Question, Input: "1"},
+	}, recording.Verdicts)
+	assert.Empty(t, recording.Error)
+}
+
+func TestWriteAndReadRecording(t *testing.T) {
+	recording := recordSession(t, "4", "1")
+	recordingFile := filepath.Join(t.TempDir(), "session.json")
+
+	assert.NoError(t, WriteRecording(recordingFile, recording))
+	read, err := ReadRecording(recordingFile)
+	assert.NoError(t, err)
+	assert.Equal(t, recording.Verdicts, read.Verdicts)
+	assert.Equal(t, recording.AnswerMaps, read.AnswerMaps)
+
+	recording.Version = 99
+	assert.NoError(t, WriteRecording(recordingFile, recording))
+	_, err = ReadRecording(recordingFile)
+	assert.EqualError(t, err, "Unsupported recording version 99 in "+recordingFile)
+}
+
+func TestReplayMatches(t *testing.T) {
+	recording := recordSession(t, "9", "4", "1")
+
+	divergences, err := Replay(&Quiz{}, recording)
+	assert.NoError(t, err)
+	assert.Empty(t, divergences)
+	assert.Equal(t, "The replay matches the recording: 3 answers verified the same way.", FormatDivergences(divergences, recording))
+}
+
+func TestReplayOfUnfinishedSession(t *testing.T) {
+	recording := recordSession(t, "4")
+	assert.Equal(t, "EOF", recording.Error)
+
+	divergences, err := Replay(&Quiz{}, recording)
+	assert.NoError(t, err)
+	assert.Empty(t, divergences)
+}
+
+func TestReplayReportsDivergences(t *testing.T) {
+	recording := recordSession(t, "4", "1")
+	recording.Verdicts[0].Correct = false
+	recording.Verdicts[1].Input = "2"
+
+	divergences, err := Replay(&Quiz{}, recording)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Verification 1 of answer '4' to 'Which language is this written in?': recorded wrong, replayed correct",
+		"Verification 2: recorded answer '2' to 'What is blue and yellow together? (using watercolors)', " +
+			"replayed answer '1' to 'What is blue and yellow together? (using watercolors)'",
+	}, divergences)
+	assert.Contains(t, FormatDivergences(divergences, recording), "The replay differs from the recording in 2 places:\n")
+
+	recording = recordSession(t, "4", "1")
+	recording.Inputs = recording.Inputs[:1]
+	divergences, err = Replay(&Quiz{}, recording)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"The replay asked for more answers than were recorded",
+		"The recorded session ended with '', the replay with 'EOF'",
+		"1 recorded verifications did not happen",
+	}, divergences)
+}
+
+func TestReplayInRecordedLanguageAndLabels(t *testing.T) {
+	swedish, _ := NewLocalizer("sv")
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion, testQuestion2}, nil)
+	quizMock.On("GetAnswerMap", testQuestion).Return(map[string]string{"A": "Ruby", "B": "Java", "C": "Python", "D": "Go"})
+	quizMock.On("GetAnswerMap", testQuestion2).Return(map[string]string{"A": "Pink", "B": "Black", "C": "Red", "D": "Green"})
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("FormatResult", mock.Anything, mock.Anything).Return("Formatted result")
+	quizMock.On("GetUserInput", mock.Anything).Return("D", nil).Once()
+	quizMock.On("GetUserInput", mock.Anything).Return("hoppa", nil).Once()
+	quizMock.On("Verify", mock.Anything, mock.Anything, "D").Return(true, nil)
+
+	recorder := NewRecorder(quizMock, Options{Player: "anna", Localizer: swedish})
+	recorder.SetLabels(LabelsLetters)
+	var stdin bytes.Buffer
+	err := RunWithOptions(recorder, &stdin, Options{Player: "anna", Localizer: swedish})
+	assert.NoError(t, err)
+	recording := recorder.Recording(err)
+	assert.Equal(t, "sv", recording.Language)
+	assert.Equal(t, LabelsLetters, recording.Labels)
+
+	// "hoppa" skips the question in Swedish only
+	divergences, err := Replay(&Quiz{}, recording)
+	assert.NoError(t, err)
+	assert.Empty(t, divergences)
+
+	recording.Language = "xx"
+	_, err = Replay(&Quiz{}, recording)
+	assert.Error(t, err)
+}
+
+func TestReplayTimesOutWhereTheRecordingDid(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion, testQuestion2}, nil)
+	quizMock.On("GetAnswerMap", testQuestion).Return(testAnswerMap)
+	quizMock.On("GetAnswerMap", testQuestion2).Return(testAnswerMap2)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("FormatResult", mock.Anything, mock.Anything).Return("Formatted result")
+	// Typed only after the time for the first question was up
+	quizMock.On("GetUserInput", mock.Anything).WaitUntil(time.After(150*time.Millisecond)).Return("1", nil).Once()
+	quizMock.On("Verify", mock.Anything, mock.Anything, "1").Return(false, nil)
+
+	options := Options{Player: "anna", TimeLimit: 100 * time.Millisecond}
+	recorder := NewRecorder(quizMock, options)
+	var stdin bytes.Buffer
+	err := RunWithOptions(recorder, &stdin, options)
+	assert.NoError(t, err)
+	recording := recorder.Recording(err)
+	assert.Equal(t, 100*time.Millisecond, recording.TimeLimit)
+	assert.Equal(t, []string{"1"}, recording.Inputs)
+	assert.Equal(t, []int{0}, recording.TimedOut)
+
+	divergences, err := Replay(&Quiz{}, recording)
+	assert.NoError(t, err)
+	assert.Empty(t, divergences)
+
+	// Without the time-out the input answers the first question
+	recording.TimedOut = nil
+	divergences, err = Replay(&Quiz{}, recording)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, divergences)
+}
@@ -0,0 +1,119 @@
+package quiz
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type RoundScore struct {
+	Name            string
+	CorrectAnswers  int
+	NumberQuestions int
+}
+
+// Plays every configured round in order, fetching the questions of each
+// round separately, and prints the per-round scores and the final total
+func (game *game) playRounds(configuration Configuration) error {
+	var scores []RoundScore
+
+	for index, round := range configuration.Rounds {
+		name := roundName(round, index, game.localizer)
+
+		questions, err := game.quiz.GetQuestions(roundConfiguration(configuration, round))
+		if err != nil {
+			return err
+		}
+
+		game.renderer.Message(formatRoundTitle(name, len(questions), game.localizer) + "\n")
+
+		game.round = name
+		correctAnswers, err := game.askQuestions(questions)
+		if err != nil {
+			return err
+		}
+
+		scores = append(scores, RoundScore{
+			Name:            name,
+			CorrectAnswers:  correctAnswers,
+			NumberQuestions: len(questions),
+		})
+		game.renderer.Score(ScoreEvent{
+			Title:           name,
+			CorrectAnswers:  correctAnswers,
+			NumberQuestions: len(questions),
+			Text:            fmt.Sprintf("%s: %s", name, game.quiz.FormatResult(correctAnswers, len(questions))),
+		})
+
+		if index < len(configuration.Rounds)-1 {
+			nextRound := roundName(configuration.Rounds[index+1], index+1, game.localizer)
+			game.renderer.Message(formatIntermission(scores, nextRound, game.localizer) + "\n")
+		}
+	}
+
+	correctAnswers, numberQuestions := totalScore(scores)
+	game.gameOver(formatRoundScores(scores, game.localizer)+"\n"+game.quiz.FormatResult(correctAnswers, numberQuestions), nil)
+
+	return nil
+}
+
+// Returns a copy of the configuration where the trivia settings are
+// overridden by the ones given for the round
+func roundConfiguration(configuration Configuration, round RoundObject) Configuration {
+	roundConfig := configuration
+	roundConfig.Rounds = nil
+
+	if round.Amount != "" {
+		roundConfig.Trivia.Amount = round.Amount
+	}
+	if round.Category != "" {
+		roundConfig.Trivia.Category = round.Category
+	}
+	if round.Difficulty != "" {
+		roundConfig.Trivia.Difficulty = round.Difficulty
+	}
+
+	return roundConfig
+}
+
+func roundName(round RoundObject, index int, localizer *Localizer) string {
+	if round.Name != "" {
+		return round.Name
+	}
+	return localizer.Text("round", index+1)
+}
+
+func formatRoundTitle(name string, numberQuestions int, localizer *Localizer) string {
+	title := localizer.Plural("round_title", numberQuestions, name, numberQuestions)
+	line := strings.Repeat("=", utf8.RuneCountInString(title))
+
+	return fmt.Sprintf("\n%s\n%s\n%s", line, title, line)
+}
+
+func formatIntermission(scores []RoundScore, nextRound string, localizer *Localizer) string {
+	correctAnswers, numberQuestions := totalScore(scores)
+
+	return fmt.Sprintf("\n%s\n%s\n%s", localizer.Text("intermission"),
+		localizer.Text("score_so_far", correctAnswers, numberQuestions), localizer.Text("up_next", nextRound))
+}
+
+func formatRoundScores(scores []RoundScore, localizer *Localizer) string {
+	var builder strings.Builder
+	builder.WriteString("\n" + localizer.Text("round_scores") + "\n")
+	for _, score := range scores {
+		builder.WriteString(localizer.Text("round_score", score.Name, score.CorrectAnswers, score.NumberQuestions) + "\n")
+	}
+
+	return builder.String()
+}
+
+func totalScore(scores []RoundScore) (int, int) {
+	correctAnswers := 0
+	numberQuestions := 0
+	for _, score := range scores {
+		correctAnswers += score.CorrectAnswers
+		numberQuestions += score.NumberQuestions
+	}
+
+	return correctAnswers, numberQuestions
+}
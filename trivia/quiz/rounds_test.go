@@ -0,0 +1,79 @@
+package quiz
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testRoundsConfiguration = Configuration{
+	QuestionFile: "testdata/questions.json",
+	Trivia: TriviaObject{
+		BaseURL: "http://trivia.com/api",
+		Amount:  "10",
+	},
+	Rounds: []RoundObject{
+		{Name: "Round 1: Science", Amount: "1", Category: "17", Difficulty: "easy"},
+		{Amount: "2", Difficulty: "hard"},
+	},
+}
+
+func TestRoundConfiguration(t *testing.T) {
+	roundConfig := roundConfiguration(testRoundsConfiguration, testRoundsConfiguration.Rounds[1])
+	assert.Equal(t, "2", roundConfig.Trivia.Amount)
+	assert.Equal(t, "", roundConfig.Trivia.Category)
+	assert.Equal(t, "hard", roundConfig.Trivia.Difficulty)
+	assert.Equal(t, "http://trivia.com/api", roundConfig.Trivia.BaseURL)
+	assert.Empty(t, roundConfig.Rounds)
+}
+
+func TestRoundName(t *testing.T) {
+	assert.Equal(t, "Round 1: Science", roundName(testRoundsConfiguration.Rounds[0], 0, nil))
+	assert.Equal(t, "Round 2", roundName(testRoundsConfiguration.Rounds[1], 1, nil))
+}
+
+func TestFormatRoundScores(t *testing.T) {
+	scores := []RoundScore{
+		{Name: "Round 1", CorrectAnswers: 1, NumberQuestions: 2},
+		{Name: "Round 2", CorrectAnswers: 3, NumberQuestions: 5},
+	}
+	expected := "\nRound scores:\n" +
+		"  Round 1: 1 of 2\n" +
+		"  Round 2: 3 of 5\n"
+	assert.Equal(t, expected, formatRoundScores(scores, nil))
+
+	correctAnswers, numberQuestions := totalScore(scores)
+	assert.Equal(t, 4, correctAnswers)
+	assert.Equal(t, 7, numberQuestions)
+}
+
+func TestRun_Rounds(t *testing.T) {
+	firstRound := roundConfiguration(testRoundsConfiguration, testRoundsConfiguration.Rounds[0])
+	secondRound := roundConfiguration(testRoundsConfiguration, testRoundsConfiguration.Rounds[1])
+
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testRoundsConfiguration, nil)
+	quizMock.On("GetQuestions", firstRound).Return([]Question{testQuestion}, nil)
+	quizMock.On("GetQuestions", secondRound).Return([]Question{testQuestion, testQuestion2}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	quizMock.On("FormatResult", mock.Anything, mock.Anything).Return("Formatted result")
+
+	var stdin bytes.Buffer
+	err := Run(quizMock, &stdin)
+
+	assert.NoError(t, err)
+	quizMock.AssertNumberOfCalls(t, "GetQuestions", 2)
+	quizMock.AssertCalled(t, "GetQuestions", firstRound)
+	quizMock.AssertCalled(t, "GetQuestions", secondRound)
+	quizMock.AssertNumberOfCalls(t, "Verify", 3)
+	quizMock.AssertCalled(t, "FormatResult", 1, 1)
+	quizMock.AssertCalled(t, "FormatResult", 0, 2)
+	quizMock.AssertCalled(t, "FormatResult", 1, 3)
+	quizMock.AssertNumberOfCalls(t, "FormatResult", 3)
+}
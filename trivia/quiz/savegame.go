@@ -0,0 +1,142 @@
+package quiz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const savedGameVersion int = 1
+
+// A classic game that was quit before the last question. The record holds
+// the answers and the score so far, its duration the time played. The time
+// limit, the labels and the shuffles already made are those of the game as it
+// was played, to continue it the same way.
+type SavedGame struct {
+	Version         int           `json:"version"`
+	Saved           time.Time     `json:"saved"`
+	NumberQuestions int           `json:"number_questions"`
+	Questions       []Question    `json:"questions"`
+	Record          GameRecord    `json:"record"`
+	TimeLimit       time.Duration `json:"time_limit,omitempty"`
+	Labels          string        `json:"labels,omitempty"`
+	Draws           []int         `json:"draws,omitempty"`
+}
+
+// Returns the file holding the player's saved game
+func SavedGameFile(dataDir string, player string) string {
+	return filepath.Join(dataDir, "saved", SafeFileName(player)+".json")
+}
+
+func WriteSavedGame(savedGameFile string, saved SavedGame) error {
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(savedGameFile, data)
+}
+
+func ReadSavedGame(savedGameFile string) (SavedGame, error) {
+	var saved SavedGame
+	file, err := ioutil.ReadFile(savedGameFile)
+	if err != nil {
+		return saved, err
+	}
+
+	err = json.Unmarshal(file, &saved)
+	if err != nil {
+		return saved, fmt.Errorf("Failed to parse saved game %s: %s", savedGameFile, err.Error())
+	}
+	if saved.Version != savedGameVersion {
+		return saved, fmt.Errorf("Unsupported saved game version %d in %s", saved.Version, savedGameFile)
+	}
+
+	return saved, nil
+}
+
+// Continues a saved game with its remaining questions. When the game is
+// played to the end it is added to the history and the saved game in
+// options.SaveFile is removed; when it is quit again it is saved again.
+func Resume(quiz QuizInterface, stdin io.Reader, saved SavedGame, options Options) error {
+	currentGame := newGameWithOptions(quiz, stdin, options)
+	currentGame.record = saved.Record
+	// Count the time played before the game was saved
+	currentGame.record.Started = time.Now().Add(-saved.Record.Duration)
+
+	_, err := currentGame.askRemaining(saved.Questions, saved.NumberQuestions-len(saved.Questions), saved.NumberQuestions)
+	if err == ErrQuit {
+		return currentGame.quit(options)
+	}
+	if err != nil {
+		return err
+	}
+	currentGame.gameOver(quiz.FormatResult(currentGame.record.CorrectAnswers, currentGame.record.NumberQuestions), nil)
+
+	if options.SaveFile != "" {
+		err = os.Remove(options.SaveFile)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if options.History == nil {
+		return nil
+	}
+	return options.History.Append(currentGame.finish())
+}
+
+// Ends a game the player quit with the score so far and saves a classic game
+// so that it can be resumed. Nothing is added to the history.
+func (game *game) quit(options Options) error {
+	resumable := game.record.Mode == "classic" && len(game.remaining) > 0
+
+	// Several players or teams get their ranking so far instead of one result
+	var scores []PlayerScore
+	var builder strings.Builder
+	builder.WriteString(game.localizer.Text("game_quit") + "\n")
+	switch game.record.Mode {
+	case "hotseat":
+		scores = PlayerScores(game.record.Answers, options.Players)
+		builder.WriteString(FormatRanking(scores, game.localizer))
+	case "team":
+		teamScores := TeamScores(teamAnswers(game.record.Answers, options.Teams, options.Consensus), options.Teams)
+		scores = teamRankingScores(teamScores)
+		builder.WriteString(FormatTeamRanking(teamScores, game.localizer))
+	default:
+		builder.WriteString(game.quiz.FormatResult(game.record.CorrectAnswers, game.record.NumberQuestions))
+	}
+	if resumable {
+		builder.WriteString("\n" + game.localizer.Plural("questions_left", len(game.remaining), len(game.remaining)))
+	}
+	game.gameOver(builder.String(), scores)
+
+	if !resumable || options.SaveFile == "" {
+		return nil
+	}
+
+	record := game.record
+	record.Duration = time.Since(record.Started)
+	saved := SavedGame{
+		Version:         savedGameVersion,
+		Saved:           time.Now(),
+		NumberQuestions: record.NumberQuestions + len(game.remaining),
+		Questions:       game.remaining,
+		Record:          record,
+		TimeLimit:       options.TimeLimit,
+		Labels:          options.Labels,
+	}
+	if counter, ok := game.quiz.(drawCounter); ok {
+		saved.Draws = counter.Draws()
+	}
+	err := WriteSavedGame(options.SaveFile, saved)
+	if err != nil {
+		return err
+	}
+	game.renderer.Message(game.localizer.Text("game_saved") + "\n")
+
+	return nil
+}
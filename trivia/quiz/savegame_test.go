@@ -0,0 +1,126 @@
+package quiz
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSavedGameFile(t *testing.T) {
+	assert.Equal(t, filepath.Join("data", "saved", "alice.json"), SavedGameFile("data", "alice"))
+}
+
+func TestReadSavedGame_Missing(t *testing.T) {
+	_, err := ReadSavedGame(filepath.Join(t.TempDir(), "missing.json"))
+
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestReadSavedGame_UnsupportedVersion(t *testing.T) {
+	saveFile := filepath.Join(t.TempDir(), "alice.json")
+	assert.NoError(t, WriteSavedGame(saveFile, SavedGame{Version: 2}))
+
+	_, err := ReadSavedGame(saveFile)
+
+	assert.EqualError(t, err, "Unsupported saved game version 2 in "+saveFile)
+}
+
+func TestRunWithOptions_QuitSavesGame(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion, testQuestion2, testQuestion}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("")
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil).Once()
+	quizMock.On("GetUserInput", mock.Anything).Return("quit", nil).Once()
+	quizMock.On("Verify", mock.Anything, mock.Anything, "4").Return(true, nil)
+	quizMock.On("FormatResult", 1, 1).Return("Formatted result")
+
+	var out bytes.Buffer
+	saveFile := SavedGameFile(t.TempDir(), "alice")
+	err := RunWithOptions(quizMock, nil, Options{Player: "alice", Seed: 42, SaveFile: saveFile, Renderer: NewPlainRenderer(&out, nil),
+		TimeLimit: time.Minute, Labels: LabelsLetters})
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "Game quit.\nFormatted result\n2 questions are left.\n")
+	assert.Contains(t, out.String(), "The game is saved, continue it with 'trivia resume'.")
+	saved, err := ReadSavedGame(saveFile)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, saved.NumberQuestions)
+	assert.Equal(t, []Question{testQuestion2, testQuestion}, saved.Questions)
+	assert.Equal(t, "alice", saved.Record.Player)
+	assert.Equal(t, int64(42), saved.Record.Seed)
+	assert.Equal(t, 1, saved.Record.CorrectAnswers)
+	assert.Len(t, saved.Record.Answers, 1)
+	assert.Equal(t, time.Minute, saved.TimeLimit)
+	assert.Equal(t, LabelsLetters, saved.Labels)
+}
+
+func TestRunWithOptions_Interrupt(t *testing.T) {
+	typed := make(chan time.Time)
+	defer close(typed)
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion, testQuestion2}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("")
+	// The player never finishes typing
+	quizMock.On("GetUserInput", mock.Anything).WaitUntil(typed).Return("4", nil)
+	quizMock.On("FormatResult", 0, 0).Return("Formatted result")
+	interrupt := make(chan os.Signal, 1)
+	interrupt <- os.Interrupt
+
+	var out bytes.Buffer
+	saveFile := SavedGameFile(t.TempDir(), "alice")
+	err := RunWithOptions(quizMock, nil, Options{SaveFile: saveFile, Interrupt: interrupt, Renderer: NewPlainRenderer(&out, nil)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1/2\nGame quit.\nFormatted result\n2 questions are left.\n"+
+		"The game is saved, continue it with 'trivia resume'.\n", out.String())
+	quizMock.AssertNotCalled(t, "Verify", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResume(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("")
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, "4").Return(true, nil)
+	quizMock.On("FormatResult", 2, 2).Return("Formatted result")
+
+	dataDir := t.TempDir()
+	saveFile := SavedGameFile(dataDir, "alice")
+	saved := SavedGame{
+		Version:         savedGameVersion,
+		NumberQuestions: 2,
+		Questions:       []Question{testQuestion2},
+		Record: GameRecord{
+			Player:          "alice",
+			Mode:            "classic",
+			Duration:        time.Minute,
+			NumberQuestions: 1,
+			CorrectAnswers:  1,
+			Answers:         []AnswerRecord{{Question: testQuestion, Choice: "4", Correct: true}},
+		},
+	}
+	assert.NoError(t, WriteSavedGame(saveFile, saved))
+
+	var out bytes.Buffer
+	history := NewHistory(dataDir)
+	err := Resume(quizMock, nil, saved, Options{SaveFile: saveFile, History: history, Renderer: NewPlainRenderer(&out, nil)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2/2\nYour answer is correct.\n\nFormatted result\n", out.String())
+	records, _ := history.Records()
+	assert.Len(t, records, 1)
+	assert.Equal(t, 2, records[0].NumberQuestions)
+	assert.Equal(t, 2, records[0].CorrectAnswers)
+	assert.True(t, records[0].Duration >= time.Minute)
+	_, err = os.Stat(saveFile)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,134 @@
+package quiz
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// A single-player game that is played one answer at a time instead of
+// reading from stdin, e.g. by a client of the HTTP API
+type Session struct {
+	quiz      QuizInterface
+	questions []Question
+	answerMap map[string]string
+	asked     time.Time
+	record    GameRecord
+}
+
+// Starts a session with a random ID, which a client cannot guess to answer
+// for another player
+func NewSession(quiz QuizInterface, player string, configuration Configuration, questions []Question) (*Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	session := &Session{
+		quiz:      quiz,
+		questions: questions,
+		record: GameRecord{
+			ID:            id,
+			Player:        player,
+			Started:       started,
+			Mode:          "classic",
+			Configuration: configuration,
+		},
+	}
+	session.nextQuestion()
+
+	return session, nil
+}
+
+func newSessionID() (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", fmt.Errorf("Failed to create a session ID: %s", err.Error())
+	}
+	return hex.EncodeToString(id), nil
+}
+
+func (session *Session) ID() string {
+	return session.record.ID
+}
+
+func (session *Session) Player() string {
+	return session.record.Player
+}
+
+// Returns the number of the current question, counting from 1
+func (session *Session) Number() int {
+	return len(session.record.Answers) + 1
+}
+
+func (session *Session) NumberQuestions() int {
+	return len(session.questions)
+}
+
+func (session *Session) CorrectAnswers() int {
+	return session.record.CorrectAnswers
+}
+
+func (session *Session) Finished() bool {
+	return len(session.record.Answers) >= len(session.questions)
+}
+
+// Returns the question to answer and its options. Returns false when all
+// questions have been answered.
+func (session *Session) Current() (Question, map[string]string, bool) {
+	if session.Finished() {
+		return Question{}, nil, false
+	}
+	return session.questions[len(session.record.Answers)], session.answerMap, true
+}
+
+// Answers the current question and moves on to the next one
+func (session *Session) Answer(choice string) (AnswerRecord, error) {
+	question, answerMap, found := session.Current()
+	if !found {
+		return AnswerRecord{}, fmt.Errorf("The game is over")
+	}
+
+	correct, err := session.quiz.Verify(question, answerMap, choice)
+	if err != nil {
+		return AnswerRecord{}, err
+	}
+	choice = NormalizeChoice(choice)
+	answer := AnswerRecord{
+		Question:  question,
+		AnswerMap: answerMap,
+		Choice:    choice,
+		Answer:    answerMap[choice],
+		Correct:   correct,
+		Duration:  time.Since(session.asked),
+	}
+	session.record.Answers = append(session.record.Answers, answer)
+	session.record.NumberQuestions++
+	if correct {
+		session.record.CorrectAnswers++
+	}
+	session.nextQuestion()
+
+	return answer, nil
+}
+
+// Returns the record of the game, complete once the session is finished
+func (session *Session) Record() GameRecord {
+	record := session.record
+	record.Duration = time.Since(record.Started)
+
+	return record
+}
+
+func (session *Session) nextQuestion() {
+	question, _, found := session.Current()
+	if !found {
+		session.answerMap = nil
+		return
+	}
+	session.answerMap = session.quiz.GetAnswerMap(question)
+	session.asked = time.Now()
+}
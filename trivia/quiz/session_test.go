@@ -0,0 +1,63 @@
+package quiz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSession(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("GetAnswerMap", testQuestion).Return(testAnswerMap)
+	quizMock.On("GetAnswerMap", testQuestion2).Return(testAnswerMap2)
+	quizMock.On("Verify", testQuestion, testAnswerMap, "4").Return(true, nil)
+	quizMock.On("Verify", testQuestion2, testAnswerMap2, "1").Return(false, nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(false, assert.AnError)
+
+	session, err := NewSession(quizMock, "anna", testConfiguration, []Question{testQuestion, testQuestion2})
+	assert.NoError(t, err)
+	assert.Equal(t, "anna", session.Player())
+	assert.Len(t, session.ID(), 32)
+	assert.Equal(t, 2, session.NumberQuestions())
+
+	question, answerMap, found := session.Current()
+	assert.True(t, found)
+	assert.Equal(t, testQuestion, question)
+	assert.Equal(t, testAnswerMap, answerMap)
+
+	_, err = session.Answer("X")
+	assert.Equal(t, assert.AnError, err)
+	assert.Equal(t, 1, session.Number())
+
+	answer, err := session.Answer("4")
+	assert.NoError(t, err)
+	assert.True(t, answer.Correct)
+	assert.Equal(t, "Go", answer.Answer)
+	assert.Equal(t, 2, session.Number())
+
+	answer, _ = session.Answer("1")
+	assert.False(t, answer.Correct)
+	assert.True(t, session.Finished())
+	_, _, found = session.Current()
+	assert.False(t, found)
+
+	_, err = session.Answer("1")
+	assert.EqualError(t, err, "The game is over")
+
+	record := session.Record()
+	assert.Equal(t, session.ID(), record.ID)
+	assert.Equal(t, "classic", record.Mode)
+	assert.Equal(t, 1, record.CorrectAnswers)
+	assert.Equal(t, 2, record.NumberQuestions)
+	assert.Len(t, record.Answers, 2)
+}
+
+func TestNewSession_UniqueIDs(t *testing.T) {
+	first, err := NewSession(&QuizMock{}, "anna", testConfiguration, nil)
+	assert.NoError(t, err)
+	second, err := NewSession(&QuizMock{}, "anna", testConfiguration, nil)
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, first.ID(), second.ID())
+}
@@ -0,0 +1,209 @@
+package quiz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const unknownGroup string = "Unknown"
+
+// Number of weakest topics listed in the statistics
+const weakestTopicsCount int = 3
+
+type AccuracyStats struct {
+	Name                   string  `json:"name"`
+	CorrectAnswers         int     `json:"correct_answers"`
+	NumberQuestions        int     `json:"number_questions"`
+	Accuracy               float64 `json:"accuracy"`
+	AverageResponseSeconds float64 `json:"average_response_seconds"`
+	totalDuration          time.Duration
+}
+
+type TrendPoint struct {
+	Date            string  `json:"date"`
+	Games           int     `json:"games"`
+	CorrectAnswers  int     `json:"correct_answers"`
+	NumberQuestions int     `json:"number_questions"`
+	Accuracy        float64 `json:"accuracy"`
+}
+
+type Statistics struct {
+	Player                 string          `json:"player,omitempty"`
+	Games                  int             `json:"games"`
+	CorrectAnswers         int             `json:"correct_answers"`
+	NumberQuestions        int             `json:"number_questions"`
+	Accuracy               float64         `json:"accuracy"`
+	AverageResponseSeconds float64         `json:"average_response_seconds"`
+	Categories             []AccuracyStats `json:"categories"`
+	Difficulties           []AccuracyStats `json:"difficulties"`
+	WeakestTopics          []AccuracyStats `json:"weakest_topics"`
+	Trend                  []TrendPoint    `json:"trend"`
+}
+
+// Aggregates the answers of the recorded games into accuracy per category
+// and difficulty, response times and accuracy per day
+func Stats(records []GameRecord, player string) Statistics {
+	statistics := Statistics{Player: player}
+	total := &AccuracyStats{}
+	categories := map[string]*AccuracyStats{}
+	difficulties := map[string]*AccuracyStats{}
+	trend := map[string]*TrendPoint{}
+
+	for _, record := range records {
+		date := record.Started.Format(dateLayout)
+		point, found := trend[date]
+		if !found {
+			point = &TrendPoint{Date: date}
+			trend[date] = point
+		}
+		point.Games++
+		statistics.Games++
+
+		for _, answer := range record.Answers {
+			category := questionCategory(record.Configuration, answer.Question)
+			difficulty := questionDifficulty(record.Configuration, answer.Question)
+			if categories[category] == nil {
+				categories[category] = &AccuracyStats{Name: category}
+			}
+			if difficulties[difficulty] == nil {
+				difficulties[difficulty] = &AccuracyStats{Name: difficulty}
+			}
+
+			for _, group := range []*AccuracyStats{total, categories[category], difficulties[difficulty]} {
+				group.add(answer)
+			}
+			point.NumberQuestions++
+			if answer.Correct {
+				point.CorrectAnswers++
+			}
+		}
+	}
+
+	total.finish()
+	statistics.CorrectAnswers = total.CorrectAnswers
+	statistics.NumberQuestions = total.NumberQuestions
+	statistics.Accuracy = total.Accuracy
+	statistics.AverageResponseSeconds = total.AverageResponseSeconds
+	statistics.Categories = sortedGroups(categories)
+	statistics.Difficulties = sortedGroups(difficulties)
+	statistics.WeakestTopics = weakestTopics(statistics.Categories)
+
+	for _, point := range trend {
+		point.Accuracy = accuracy(point.CorrectAnswers, point.NumberQuestions)
+		statistics.Trend = append(statistics.Trend, *point)
+	}
+	sort.Slice(statistics.Trend, func(i, j int) bool { return statistics.Trend[i].Date < statistics.Trend[j].Date })
+
+	return statistics
+}
+
+func (group *AccuracyStats) add(answer AnswerRecord) {
+	group.NumberQuestions++
+	group.totalDuration += answer.Duration
+	if answer.Correct {
+		group.CorrectAnswers++
+	}
+}
+
+func (group *AccuracyStats) finish() {
+	group.Accuracy = accuracy(group.CorrectAnswers, group.NumberQuestions)
+	if group.NumberQuestions > 0 {
+		group.AverageResponseSeconds = group.totalDuration.Seconds() / float64(group.NumberQuestions)
+	}
+}
+
+func accuracy(correctAnswers int, numberQuestions int) float64 {
+	if numberQuestions == 0 {
+		return 0
+	}
+	return float64(correctAnswers) / float64(numberQuestions)
+}
+
+func questionCategory(configuration Configuration, question Question) string {
+	if question.Category != "" {
+		return question.Category
+	}
+	if configuration.Trivia.Category != "" {
+		return configuration.Trivia.Category
+	}
+	return unknownGroup
+}
+
+func questionDifficulty(configuration Configuration, question Question) string {
+	if question.Difficulty != "" {
+		return question.Difficulty
+	}
+	if configuration.Trivia.Difficulty != "" {
+		return configuration.Trivia.Difficulty
+	}
+	return unknownGroup
+}
+
+func sortedGroups(groups map[string]*AccuracyStats) []AccuracyStats {
+	var sorted []AccuracyStats
+	for _, group := range groups {
+		group.finish()
+		sorted = append(sorted, *group)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	return sorted
+}
+
+// Returns the categories with the lowest accuracy, most answered first
+// among equals
+func weakestTopics(categories []AccuracyStats) []AccuracyStats {
+	weakest := append([]AccuracyStats{}, categories...)
+	sort.SliceStable(weakest, func(i, j int) bool {
+		if weakest[i].Accuracy != weakest[j].Accuracy {
+			return weakest[i].Accuracy < weakest[j].Accuracy
+		}
+		return weakest[i].NumberQuestions > weakest[j].NumberQuestions
+	})
+	if len(weakest) > weakestTopicsCount {
+		weakest = weakest[:weakestTopicsCount]
+	}
+
+	return weakest
+}
+
+func FormatStats(statistics Statistics) string {
+	if statistics.Games == 0 {
+		return "No games recorded."
+	}
+
+	var builder strings.Builder
+	if statistics.Player != "" {
+		builder.WriteString(fmt.Sprintf("Statistics for %s\n", statistics.Player))
+	}
+	builder.WriteString(fmt.Sprintf("Games played:     %d\n", statistics.Games))
+	builder.WriteString(fmt.Sprintf("Correct answers:  %d of %d (%s)\n",
+		statistics.CorrectAnswers, statistics.NumberQuestions, formatPercent(statistics.Accuracy)))
+	builder.WriteString(fmt.Sprintf("Average response: %.1fs\n", statistics.AverageResponseSeconds))
+
+	writeGroups(&builder, "By category", statistics.Categories)
+	writeGroups(&builder, "By difficulty", statistics.Difficulties)
+	writeGroups(&builder, "Weakest topics", statistics.WeakestTopics)
+
+	builder.WriteString("\nTrend:\n")
+	for _, point := range statistics.Trend {
+		builder.WriteString(fmt.Sprintf("  %s  %d games  %d/%d  %s\n",
+			point.Date, point.Games, point.CorrectAnswers, point.NumberQuestions, formatPercent(point.Accuracy)))
+	}
+
+	return builder.String()
+}
+
+func writeGroups(builder *strings.Builder, title string, groups []AccuracyStats) {
+	builder.WriteString(fmt.Sprintf("\n%s:\n", title))
+	for _, group := range groups {
+		builder.WriteString(fmt.Sprintf("  %-30s %d/%d  %s  %.1fs\n",
+			group.Name, group.CorrectAnswers, group.NumberQuestions, formatPercent(group.Accuracy), group.AverageResponseSeconds))
+	}
+}
+
+func formatPercent(value float64) string {
+	return fmt.Sprintf("%.0f%%", value*100)
+}
@@ -0,0 +1,72 @@
+package quiz
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func statsRecords() []GameRecord {
+	unknownQuestion := Question{Question: "Unknown?"}
+	configured := leaderboardRecord("3", "anna", testNow.AddDate(0, 0, 1))
+	configured.Configuration.Trivia.Difficulty = "medium"
+	configured.Answers = []AnswerRecord{{Question: unknownQuestion, Correct: true, Duration: 4 * time.Second}}
+
+	return []GameRecord{
+		leaderboardRecord("1", "anna", testNow, true, false),
+		leaderboardRecord("2", "anna", testNow, true, false, false),
+		configured,
+	}
+}
+
+func TestStats(t *testing.T) {
+	statistics := Stats(statsRecords(), "anna")
+
+	assert.Equal(t, "anna", statistics.Player)
+	assert.Equal(t, 3, statistics.Games)
+	assert.Equal(t, 3, statistics.CorrectAnswers)
+	assert.Equal(t, 6, statistics.NumberQuestions)
+	assert.Equal(t, 0.5, statistics.Accuracy)
+	assert.InDelta(t, 13.0/6.0, statistics.AverageResponseSeconds, 0.001)
+
+	assert.Equal(t, []AccuracyStats{
+		{Name: "History", NumberQuestions: 2, AverageResponseSeconds: 2, totalDuration: 4 * time.Second},
+		{Name: "Science & Nature", CorrectAnswers: 2, NumberQuestions: 3, Accuracy: 2.0 / 3.0, AverageResponseSeconds: 5.0 / 3.0, totalDuration: 5 * time.Second},
+		{Name: "Unknown", CorrectAnswers: 1, NumberQuestions: 1, Accuracy: 1, AverageResponseSeconds: 4, totalDuration: 4 * time.Second},
+	}, statistics.Categories)
+
+	assert.Equal(t, "easy", statistics.Difficulties[0].Name)
+	assert.Equal(t, "hard", statistics.Difficulties[1].Name)
+	assert.Equal(t, "medium", statistics.Difficulties[2].Name)
+
+	assert.Equal(t, "History", statistics.WeakestTopics[0].Name)
+	assert.Equal(t, "Science & Nature", statistics.WeakestTopics[1].Name)
+
+	assert.Equal(t, []TrendPoint{
+		{Date: "2021-03-08", Games: 2, CorrectAnswers: 2, NumberQuestions: 5, Accuracy: 0.4},
+		{Date: "2021-03-09", Games: 1, CorrectAnswers: 1, NumberQuestions: 1, Accuracy: 1},
+	}, statistics.Trend)
+}
+
+func TestStatsNoGames(t *testing.T) {
+	statistics := Stats(nil, "")
+	assert.Equal(t, 0, statistics.Games)
+	assert.Equal(t, 0.0, statistics.Accuracy)
+	assert.Equal(t, "No games recorded.", FormatStats(statistics))
+}
+
+func TestStatsJSON(t *testing.T) {
+	data, err := json.Marshal(Stats(statsRecords(), "anna"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"weakest_topics":[{"name":"History","correct_answers":0,"number_questions":2`)
+}
+
+func TestFormatStats(t *testing.T) {
+	formatted := FormatStats(Stats(statsRecords(), "anna"))
+	assert.Contains(t, formatted, "Statistics for anna\n")
+	assert.Contains(t, formatted, "Correct answers:  3 of 6 (50%)\n")
+	assert.Contains(t, formatted, "  History                        0/2  0%  2.0s\n")
+	assert.Contains(t, formatted, "  2021-03-08  2 games  2/5  40%\n")
+}
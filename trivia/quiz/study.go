@@ -0,0 +1,158 @@
+package quiz
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const dateLayout string = "2006-01-02"
+
+// Days until the next review for each Leitner box. A card starts in the
+// first box, moves one box up for every correct answer and goes back to the
+// first box when it is answered wrong.
+var leitnerIntervals = []int{1, 2, 4, 8, 16}
+
+type StudyCard struct {
+	Box      int    `json:"box"`
+	Due      string `json:"due"`
+	Reviews  int    `json:"reviews"`
+	Lapses   int    `json:"lapses"`
+	Question string `json:"question"`
+}
+
+type StudyState struct {
+	Player string               `json:"player"`
+	Cards  map[string]StudyCard `json:"cards"`
+}
+
+// Returns a stable key identifying the question in review state and history
+func QuestionKey(question Question) string {
+	sum := sha1.Sum([]byte(question.Question))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// Returns the file holding the review state of the player
+func StudyStateFile(dataDir string, player string) string {
+	return filepath.Join(dataDir, "study", SafeFileName(player)+".json")
+}
+
+// Reads the review state of a player. A missing file gives an empty state.
+func ReadStudyState(stateFile string, player string) (StudyState, error) {
+	state := StudyState{Player: player, Cards: map[string]StudyCard{}}
+
+	file, err := ioutil.ReadFile(stateFile)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+
+	err = json.Unmarshal(file, &state)
+	if err != nil {
+		return state, fmt.Errorf("Failed to parse study state %s: %s", stateFile, err.Error())
+	}
+	if state.Cards == nil {
+		state.Cards = map[string]StudyCard{}
+	}
+
+	return state, nil
+}
+
+func WriteStudyState(stateFile string, state StudyState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(stateFile, data)
+}
+
+// Returns the questions that are due on the given day. Questions that have
+// never been reviewed are always due.
+func (state StudyState) DueQuestions(questions []Question, today time.Time) []Question {
+	var due []Question
+	date := today.Format(dateLayout)
+	for _, question := range questions {
+		card, found := state.Cards[QuestionKey(question)]
+		if !found || card.Due <= date {
+			due = append(due, question)
+		}
+	}
+
+	return due
+}
+
+// Moves the card of the question to its next Leitner box and schedules the
+// next review
+func (state StudyState) Review(question Question, isAnswerCorrect bool, today time.Time) StudyCard {
+	key := QuestionKey(question)
+	card, found := state.Cards[key]
+	if !found {
+		card = StudyCard{Box: 1}
+	}
+
+	card.Reviews++
+	card.Question = question.Question
+	if isAnswerCorrect {
+		if card.Box < len(leitnerIntervals) {
+			card.Box++
+		}
+	} else {
+		card.Box = 1
+		card.Lapses++
+	}
+	card.Due = today.AddDate(0, 0, leitnerIntervals[card.Box-1]).Format(dateLayout)
+	state.Cards[key] = card
+
+	return card
+}
+
+// Asks the due questions and updates the review state with the answers.
+// Returns the number of correct answers, and ErrQuit after printing the score
+// so far when the player quits.
+func Study(quiz QuizInterface, stdin io.Reader, questions []Question, state StudyState, today time.Time, options Options) (int, error) {
+	due := state.DueQuestions(questions, today)
+	studyGame := newGameWithOptions(quiz, stdin, options)
+	correctAnswers := 0
+
+	for index, question := range due {
+		isAnswerCorrect, err := studyGame.askQuestion(question, index+1, len(due))
+		// A card answered right before quitting is reviewed as well
+		if err != nil && len(studyGame.record.Answers) == index {
+			return correctAnswers, studyGame.endStudy(err, options)
+		}
+		if isAnswerCorrect {
+			correctAnswers++
+		}
+		card := state.Review(question, isAnswerCorrect, today)
+		studyGame.renderer.Message(studyGame.localizer.Text("next_review", card.Due, card.Box) + "\n")
+		if err != nil {
+			return correctAnswers, studyGame.endStudy(err, options)
+		}
+	}
+
+	return correctAnswers, nil
+}
+
+// Prints the score of the cards reviewed so far when the player quit
+func (game *game) endStudy(err error, options Options) error {
+	if err != ErrQuit {
+		return err
+	}
+	quitErr := game.quit(options)
+	if quitErr != nil {
+		return quitErr
+	}
+	return ErrQuit
+}
+
+func FormatDue(numberDue int, numberCards int, localizer *Localizer) string {
+	return localizer.Plural("cards_due", numberCards, numberDue, numberCards)
+}
@@ -0,0 +1,121 @@
+package quiz
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testToday = time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestQuestionKey(t *testing.T) {
+	assert.Equal(t, QuestionKey(testQuestion), QuestionKey(testQuestion))
+	assert.NotEqual(t, QuestionKey(testQuestion), QuestionKey(testQuestion2))
+	assert.Len(t, QuestionKey(testQuestion), 12)
+}
+
+func TestSafeFileName(t *testing.T) {
+	assert.Equal(t, "anna_b", SafeFileName("anna b"))
+	assert.Equal(t, "___", SafeFileName("../"))
+	assert.Equal(t, "_", SafeFileName(""))
+}
+
+func TestReadStudyStateMissingFile(t *testing.T) {
+	state, err := ReadStudyState(filepath.Join(t.TempDir(), "missing.json"), "anna")
+	assert.NoError(t, err)
+	assert.Equal(t, "anna", state.Player)
+	assert.Empty(t, state.Cards)
+}
+
+func TestWriteAndReadStudyState(t *testing.T) {
+	stateFile := StudyStateFile(t.TempDir(), "anna")
+	state, _ := ReadStudyState(stateFile, "anna")
+	state.Review(testQuestion, true, testToday)
+
+	err := WriteStudyState(stateFile, state)
+	assert.NoError(t, err)
+
+	readState, err := ReadStudyState(stateFile, "anna")
+	assert.NoError(t, err)
+	assert.Equal(t, state, readState)
+}
+
+func TestReview(t *testing.T) {
+	state := StudyState{Cards: map[string]StudyCard{}}
+
+	card := state.Review(testQuestion, true, testToday)
+	assert.Equal(t, 2, card.Box)
+	assert.Equal(t, "2021-03-03", card.Due)
+
+	card = state.Review(testQuestion, true, testToday)
+	assert.Equal(t, 3, card.Box)
+	assert.Equal(t, "2021-03-05", card.Due)
+
+	card = state.Review(testQuestion, false, testToday)
+	assert.Equal(t, 1, card.Box)
+	assert.Equal(t, "2021-03-02", card.Due)
+	assert.Equal(t, 3, card.Reviews)
+	assert.Equal(t, 1, card.Lapses)
+}
+
+func TestDueQuestions(t *testing.T) {
+	state := StudyState{Cards: map[string]StudyCard{}}
+	state.Review(testQuestion, true, testToday)
+
+	questions := []Question{testQuestion, testQuestion2}
+	assert.Equal(t, []Question{testQuestion2}, state.DueQuestions(questions, testToday))
+	assert.Equal(t, questions, state.DueQuestions(questions, testToday.AddDate(0, 0, 2)))
+}
+
+func TestStudy(t *testing.T) {
+	state := StudyState{Cards: map[string]StudyCard{}}
+	state.Review(testQuestion2, true, testToday)
+
+	quizMock := &QuizMock{}
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("GetUserInput", mock.Anything).Return("1", nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+
+	var stdin bytes.Buffer
+	var out bytes.Buffer
+	correctAnswers, err := Study(quizMock, &stdin, []Question{testQuestion, testQuestion2}, state, testToday,
+		Options{Renderer: NewAccessibleRenderer(&out, nil)})
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "Question 1 of 1.")
+	assert.Contains(t, out.String(), "Next review: 2021-03-02 (box 1)\n")
+	assert.Equal(t, 0, correctAnswers)
+	quizMock.AssertNumberOfCalls(t, "Verify", 1)
+	quizMock.AssertCalled(t, "FormatQuestion", testQuestion, testAnswerMap)
+	assert.Equal(t, 1, state.Cards[QuestionKey(testQuestion)].Lapses)
+}
+
+func TestStudy_Quit(t *testing.T) {
+	state := StudyState{Cards: map[string]StudyCard{}}
+
+	quizMock := &QuizMock{}
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil).Once()
+	quizMock.On("GetUserInput", mock.Anything).Return("quit", nil).Once()
+	quizMock.On("Verify", mock.Anything, mock.Anything, "4").Return(true, nil)
+	quizMock.On("FormatResult", 1, 1).Return("Formatted result")
+
+	var out bytes.Buffer
+	correctAnswers, err := Study(quizMock, nil, []Question{testQuestion, testQuestion2}, state, testToday,
+		Options{Renderer: NewPlainRenderer(&out, nil)})
+
+	assert.Equal(t, ErrQuit, err)
+	assert.Equal(t, 1, correctAnswers)
+	assert.Contains(t, out.String(), "Game quit.\nFormatted result\n")
+	assert.Len(t, state.Cards, 1)
+}
+
+func TestFormatDue(t *testing.T) {
+	assert.Equal(t, "2 of 5 cards due today.", FormatDue(2, 5, nil))
+}
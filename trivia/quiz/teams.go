@@ -0,0 +1,303 @@
+package quiz
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Consensus rules deciding the answer of a team: the answer given first, the
+// most common answer of all members, or the answer of the captain after
+// hearing the others
+const ConsensusFirst string = "first"
+const ConsensusMajority string = "majority"
+const ConsensusCaptain string = "captain"
+
+const minimumTeams int = 2
+
+// A named team of hot-seat players. The first player is the captain.
+type Team struct {
+	Name    string   `json:"name"`
+	Players []string `json:"players"`
+}
+
+// The answer that counts for a team
+type TeamAnswer struct {
+	Team     string
+	Choice   string
+	Answer   string
+	Correct  bool
+	Duration time.Duration
+}
+
+type TeamScore struct {
+	Rank            int           `json:"rank"`
+	Name            string        `json:"name"`
+	Players         []string      `json:"players"`
+	CorrectAnswers  int           `json:"correct_answers"`
+	NumberQuestions int           `json:"number_questions"`
+	Duration        time.Duration `json:"duration"`
+}
+
+// Parses a team given as NAME=PLAYER,PLAYER,...
+func ParseTeam(value string) (Team, error) {
+	separator := strings.Index(value, "=")
+	if separator < 0 {
+		return Team{}, fmt.Errorf("Team '%s' must be given as NAME=PLAYER,PLAYER", value)
+	}
+
+	team := Team{Name: strings.TrimSpace(value[:separator])}
+	for _, player := range strings.Split(value[separator+1:], ",") {
+		team.Players = append(team.Players, strings.TrimSpace(player))
+	}
+
+	return team, nil
+}
+
+// Returns the players of all teams
+func teamPlayers(teams []Team) []string {
+	var players []string
+	for _, team := range teams {
+		players = append(players, team.Players...)
+	}
+	return players
+}
+
+func validateTeams(teams []Team, consensus string) error {
+	if len(teams) < minimumTeams {
+		return fmt.Errorf("A team game needs at least %d teams, got %d", minimumTeams, len(teams))
+	}
+
+	names := map[string]bool{}
+	players := map[string]bool{}
+	for _, team := range teams {
+		if team.Name == "" {
+			return fmt.Errorf("Team names must not be empty")
+		}
+		if names[team.Name] {
+			return fmt.Errorf("Team '%s' is given more than once", team.Name)
+		}
+		names[team.Name] = true
+		if len(team.Players) == 0 {
+			return fmt.Errorf("Team '%s' has no players", team.Name)
+		}
+		for _, player := range team.Players {
+			if strings.TrimSpace(player) == "" {
+				return fmt.Errorf("Player names must not be empty")
+			}
+			if players[player] {
+				return fmt.Errorf("Player '%s' is given more than once", player)
+			}
+			players[player] = true
+		}
+	}
+
+	if consensus != "" && consensus != ConsensusFirst && consensus != ConsensusMajority && consensus != ConsensusCaptain {
+		return fmt.Errorf("Unknown consensus rule '%s', use %s, %s or %s", consensus, ConsensusFirst, ConsensusMajority, ConsensusCaptain)
+	}
+
+	return nil
+}
+
+// Plays a game where every team answers each question and prints the team
+// scoreboard at the end
+func (game *game) playTeams(configuration Configuration, teams []Team, consensus string) error {
+	questions, err := game.quiz.GetQuestions(configuration)
+	if err != nil {
+		return err
+	}
+
+	var teamAnswers []TeamAnswer
+	for index, question := range questions {
+		answerMap := game.showQuestion(question, index+1, len(questions))
+
+		var answers []TeamAnswer
+		for _, team := range teams {
+			answer, err := game.askTeam(question, answerMap, team, consensus)
+			if err != nil {
+				return err
+			}
+			answers = append(answers, answer)
+		}
+
+		var verdicts []VerdictEvent
+		for _, answer := range answers {
+			verdicts = append(verdicts, VerdictEvent{
+				Team:        answer.Team,
+				Question:    question.Question,
+				Choice:      answer.Choice,
+				Answer:      answer.Answer,
+				RightAnswer: question.RightAnswer,
+				Correct:     answer.Correct,
+			})
+		}
+		game.reveal(question, verdicts)
+		teamAnswers = append(teamAnswers, answers...)
+	}
+
+	teamScores := TeamScores(teamAnswers, teams)
+	game.gameOver(FormatTeamRanking(teamScores, game.localizer), teamRankingScores(teamScores))
+
+	return nil
+}
+
+// Returns the team scores as the scores of the game over event
+func teamRankingScores(teamScores []TeamScore) []PlayerScore {
+	var scores []PlayerScore
+	for _, score := range teamScores {
+		scores = append(scores, PlayerScore{
+			Rank:            score.Rank,
+			Name:            score.Name,
+			CorrectAnswers:  score.CorrectAnswers,
+			NumberQuestions: score.NumberQuestions,
+			Duration:        score.Duration,
+		})
+	}
+	return scores
+}
+
+// Asks the members of the team whose answers are needed and returns the
+// answer that counts for the team
+func (game *game) askTeam(question Question, answerMap map[string]string, team Team, consensus string) (TeamAnswer, error) {
+	game.renderer.Message(game.localizer.Text("team", team.Name) + "\n")
+
+	captain := team.Players[0]
+	members := team.Players
+	if consensus == ConsensusCaptain {
+		// The captain answers last, after hearing the others
+		members = append(append([]string{}, team.Players[1:]...), captain)
+	}
+
+	var answers []AnswerRecord
+	for _, member := range members {
+		game.renderer.Message(game.localizer.Text("player_answer", member))
+		answer, err := game.readAnswer(question, answerMap)
+		if err != nil {
+			return TeamAnswer{}, err
+		}
+		answer.Player = member
+		answer.Team = team.Name
+		answers = append(answers, answer)
+		game.recordAnswer(answer)
+	}
+
+	return teamDecision(answers, team, consensus), nil
+}
+
+// Returns the answer that counts for the team, from the answers of all its
+// members in the order they were asked
+func teamDecision(answers []AnswerRecord, team Team, consensus string) TeamAnswer {
+	var decision AnswerRecord
+	switch consensus {
+	case ConsensusMajority:
+		decision = majorityAnswer(answers, team.Players[0])
+	case ConsensusCaptain:
+		decision = answers[len(answers)-1]
+	default:
+		decision = firstAnswer(answers)
+	}
+
+	teamAnswer := TeamAnswer{Team: team.Name, Choice: decision.Choice, Answer: decision.Answer, Correct: decision.Correct}
+	for _, answer := range answers {
+		teamAnswer.Duration += answer.Duration
+	}
+
+	return teamAnswer
+}
+
+// Returns the team answers decided from the recorded answers of the members.
+// A team still answering a question when the game ended has no answer for it.
+func teamAnswers(answers []AnswerRecord, teams []Team, consensus string) []TeamAnswer {
+	var teamAnswers []TeamAnswer
+	for start := 0; start < len(answers); {
+		end := start + 1
+		for end < len(answers) && answers[end].Team == answers[start].Team {
+			end++
+		}
+		for _, team := range teams {
+			if team.Name == answers[start].Team && end-start == len(team.Players) {
+				teamAnswers = append(teamAnswers, teamDecision(answers[start:end], team, consensus))
+			}
+		}
+		start = end
+	}
+	return teamAnswers
+}
+
+// Returns the answer given first. Members share the terminal and answer one
+// after another, so each one is timed from their own prompt and the quickest
+// answer counts, as if they had answered at the same time. A tie goes to the
+// member asked first.
+func firstAnswer(answers []AnswerRecord) AnswerRecord {
+	first := answers[0]
+	for _, answer := range answers[1:] {
+		if answer.Duration < first.Duration {
+			first = answer
+		}
+	}
+	return first
+}
+
+// Returns the most common answer. A tie goes to the captain's answer when it
+// is one of the most common, otherwise to the one given first.
+func majorityAnswer(answers []AnswerRecord, captain string) AnswerRecord {
+	votes := map[string]int{}
+	mostVotes := 0
+	for _, answer := range answers {
+		votes[answer.Answer]++
+		if votes[answer.Answer] > mostVotes {
+			mostVotes = votes[answer.Answer]
+		}
+	}
+
+	for _, answer := range answers {
+		if answer.Player == captain && votes[answer.Answer] == mostVotes {
+			return answer
+		}
+	}
+	for _, answer := range answers {
+		if votes[answer.Answer] == mostVotes {
+			return answer
+		}
+	}
+
+	return AnswerRecord{}
+}
+
+// Returns the score of each team ranked by correct answers, with ties
+// broken by the time taken
+func TeamScores(answers []TeamAnswer, teams []Team) []TeamScore {
+	var scores []TeamScore
+	for _, team := range teams {
+		score := TeamScore{Name: team.Name, Players: team.Players}
+		for _, answer := range answers {
+			if answer.Team != team.Name {
+				continue
+			}
+			score.NumberQuestions++
+			score.Duration += answer.Duration
+			if answer.Correct {
+				score.CorrectAnswers++
+			}
+		}
+		scores = append(scores, score)
+	}
+
+	rankScores(scores,
+		func(index int) (int, time.Duration) { return scores[index].CorrectAnswers, scores[index].Duration },
+		func(index int, rank int) { scores[index].Rank = rank })
+
+	return scores
+}
+
+func FormatTeamRanking(scores []TeamScore, localizer *Localizer) string {
+	var builder strings.Builder
+	builder.WriteString(localizer.Text("team_ranking") + "\n")
+	for _, score := range scores {
+		builder.WriteString(localizer.Plural("team_ranking_entry", score.NumberQuestions,
+			score.Rank, score.Name, strings.Join(score.Players, ", "),
+			score.CorrectAnswers, score.NumberQuestions, score.Duration.Round(time.Second)) + "\n")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,222 @@
+package quiz
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testTeams = []Team{
+	{Name: "red", Players: []string{"anna", "bert", "cecilia"}},
+	{Name: "blue", Players: []string{"david", "erik"}},
+}
+
+func TestParseTeam(t *testing.T) {
+	team, err := ParseTeam("red=anna, bert")
+	assert.NoError(t, err)
+	assert.Equal(t, Team{Name: "red", Players: []string{"anna", "bert"}}, team)
+
+	_, err = ParseTeam("anna,bert")
+	assert.EqualError(t, err, "Team 'anna,bert' must be given as NAME=PLAYER,PLAYER")
+}
+
+func TestValidateTeams(t *testing.T) {
+	assert.NoError(t, validateTeams(testTeams, ""))
+	assert.NoError(t, validateTeams(testTeams, ConsensusMajority))
+	assert.EqualError(t, validateTeams(testTeams[:1], ""), "A team game needs at least 2 teams, got 1")
+	assert.EqualError(t, validateTeams([]Team{testTeams[0], testTeams[0]}, ""), "Team 'red' is given more than once")
+	assert.EqualError(t, validateTeams([]Team{testTeams[0], {Name: "blue"}}, ""), "Team 'blue' has no players")
+	assert.EqualError(t, validateTeams([]Team{testTeams[0], {Name: "blue", Players: []string{"anna"}}}, ""),
+		"Player 'anna' is given more than once")
+	assert.Error(t, validateTeams([]Team{testTeams[0], {Players: []string{"david"}}}, ""))
+	assert.Error(t, validateTeams(testTeams, "loudest"))
+}
+
+func TestMajorityAnswer(t *testing.T) {
+	answers := []AnswerRecord{
+		{Player: "anna", Answer: "Go"},
+		{Player: "bert", Answer: "Ruby"},
+		{Player: "cecilia", Answer: "Ruby"},
+	}
+	assert.Equal(t, "bert", majorityAnswer(answers, "anna").Player)
+
+	// Ties go to the captain, or to the first answer when the captain is outvoted
+	assert.Equal(t, "anna", majorityAnswer(answers[:2], "anna").Player)
+	answers = append(answers, AnswerRecord{Player: "david", Answer: "Java"}, AnswerRecord{Player: "erik", Answer: "Java"})
+	assert.Equal(t, "bert", majorityAnswer(answers, "anna").Player)
+}
+
+func TestFirstAnswer(t *testing.T) {
+	answers := []AnswerRecord{
+		{Player: "anna", Answer: "Go", Duration: 3 * time.Second},
+		{Player: "bert", Answer: "Ruby", Duration: time.Second},
+		{Player: "cecilia", Answer: "Java", Duration: time.Second},
+	}
+	assert.Equal(t, "bert", firstAnswer(answers).Player)
+	assert.Equal(t, "anna", firstAnswer(answers[:1]).Player)
+}
+
+func TestTeamScores(t *testing.T) {
+	answers := []TeamAnswer{
+		{Team: "red", Correct: true, Duration: 3 * time.Second},
+		{Team: "blue", Correct: true, Duration: 2 * time.Second},
+		{Team: "red", Correct: true, Duration: 3 * time.Second},
+		{Team: "blue", Correct: false, Duration: 2 * time.Second},
+	}
+
+	scores := TeamScores(answers, testTeams)
+	assert.Equal(t, []TeamScore{
+		{Rank: 1, Name: "red", Players: testTeams[0].Players, CorrectAnswers: 2, NumberQuestions: 2, Duration: 6 * time.Second},
+		{Rank: 2, Name: "blue", Players: testTeams[1].Players, CorrectAnswers: 1, NumberQuestions: 2, Duration: 4 * time.Second},
+	}, scores)
+
+	expected := "Team ranking:\n" +
+		"  1. red (anna, bert, cecilia): 2 of 2 correct answers (6s)\n" +
+		"  2. blue (david, erik): 1 of 2 correct answers (4s)\n"
+	assert.Equal(t, expected, FormatTeamRanking(scores, nil))
+
+	expected = "Lagställning:\n" +
+		"  1. red (anna, bert, cecilia): 2 rätt av 2 frågor (6s)\n" +
+		"  2. blue (david, erik): 1 rätt av 2 frågor (4s)\n"
+	assert.Equal(t, expected, FormatTeamRanking(scores, swedish(t)))
+}
+
+func teamQuizMock(inputs ...string) *QuizMock {
+	quizMock := &QuizMock{}
+	quizMock.On("ReadConfigurationFromYAML", mock.Anything).Return(testConfiguration, nil)
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion, testQuestion}, nil)
+	quizMock.On("GetAnswerMap", mock.Anything).Return(testAnswerMap)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	for _, input := range inputs {
+		quizMock.On("GetUserInput", mock.Anything).Return(input, nil).Once()
+	}
+	quizMock.On("Verify", mock.Anything, mock.Anything, "4").Return(true, nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	return quizMock
+}
+
+func TestRun_TeamsFirstAnswer(t *testing.T) {
+	// Every member answers: anna, bert, cecilia, then david, erik
+	quizMock := teamQuizMock("4", "4", "4", "1", "1", "1", "1", "1", "4", "4")
+
+	history := NewHistory(t.TempDir())
+	var stdin bytes.Buffer
+	err := RunWithOptions(quizMock, &stdin, Options{Teams: testTeams, History: history})
+
+	assert.NoError(t, err)
+	quizMock.AssertNumberOfCalls(t, "GetUserInput", 10)
+	quizMock.AssertNumberOfCalls(t, "FormatQuestion", 2)
+
+	records, _ := history.Records()
+	assert.Len(t, records, 5)
+	assert.Equal(t, "team", records[0].Mode)
+	assert.Equal(t, "anna", records[0].Player)
+	assert.Equal(t, 1, records[0].CorrectAnswers)
+	assert.Equal(t, 2, records[0].NumberQuestions)
+	assert.Equal(t, "red", records[0].Answers[0].Team)
+	assert.Equal(t, "bert", records[1].Player)
+	assert.Equal(t, 1, records[1].CorrectAnswers)
+	assert.Equal(t, 2, records[2].NumberQuestions)
+	assert.Equal(t, "erik", records[4].Player)
+	assert.Equal(t, 1, records[4].CorrectAnswers)
+}
+
+func TestRun_TeamsQuit(t *testing.T) {
+	// Red is still answering the second question when anna quits
+	quizMock := teamQuizMock("4", "4", "4", "1", "1", "4", "quit")
+
+	var out bytes.Buffer
+	var stdin bytes.Buffer
+	err := RunWithOptions(quizMock, &stdin, Options{Teams: testTeams, Renderer: NewPlainRenderer(&out, nil)})
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "Game quit.\nTeam ranking:\n"+
+		"  1. red (anna, bert, cecilia): 1 of 1 correct answer (0s)\n"+
+		"  2. blue (david, erik): 0 of 1 correct answer (0s)\n")
+}
+
+func TestTeamAnswers(t *testing.T) {
+	answers := []AnswerRecord{
+		{Team: "red", Player: "anna", Answer: "Go", Correct: true, Duration: time.Second},
+		{Team: "red", Player: "bert", Answer: "Java", Duration: time.Second},
+		{Team: "red", Player: "cecilia", Answer: "Java", Duration: time.Second},
+		{Team: "blue", Player: "david", Answer: "Go", Correct: true, Duration: time.Second},
+	}
+
+	assert.Equal(t, []TeamAnswer{{Team: "red", Answer: "Java", Duration: 3 * time.Second}},
+		teamAnswers(answers, testTeams, ConsensusMajority))
+}
+
+func TestRun_TeamsMajorityAndCaptain(t *testing.T) {
+	for _, test := range []struct {
+		consensus string
+		inputs    []string
+	}{
+		// anna, bert, cecilia, then david, erik for each question
+		{ConsensusMajority, []string{"1", "4", "4", "4", "1", "4", "4", "4", "1", "4"}},
+		// bert, cecilia, anna (captain), then erik, david (captain)
+		{ConsensusCaptain, []string{"1", "1", "4", "4", "1", "4", "4", "4", "1", "4"}},
+	} {
+		quizMock := teamQuizMock(test.inputs...)
+
+		var stdin bytes.Buffer
+		err := RunWithOptions(quizMock, &stdin, Options{Teams: testTeams, Consensus: test.consensus})
+
+		assert.NoError(t, err, test.consensus)
+		quizMock.AssertNumberOfCalls(t, "GetUserInput", 10)
+	}
+}
+
+func TestRun_TeamsInvalid(t *testing.T) {
+	quizMock := &QuizMock{}
+
+	var stdin bytes.Buffer
+	err := RunWithOptions(quizMock, &stdin, Options{Teams: testTeams[:1]})
+
+	assert.Error(t, err)
+	quizMock.AssertNotCalled(t, "ReadConfigurationFromYAML", mock.Anything)
+}
+
+func TestAskTeam(t *testing.T) {
+	for _, test := range []struct {
+		consensus string
+		inputs    []string
+		first     string
+		correct   bool
+	}{
+		{ConsensusMajority, []string{"1", "4", "4"}, "anna", true},
+		// The captain answers last and overrules the others
+		{ConsensusCaptain, []string{"4", "4", "1"}, "bert", false},
+	} {
+		quizMock := teamQuizMock(test.inputs...)
+		currentGame := newGame(quizMock, &bytes.Buffer{})
+
+		answer, err := currentGame.askTeam(testQuestion, testAnswerMap, testTeams[0], test.consensus)
+
+		assert.NoError(t, err, test.consensus)
+		assert.Equal(t, "red", answer.Team, test.consensus)
+		assert.Equal(t, test.correct, answer.Correct, test.consensus)
+		assert.Len(t, currentGame.record.Answers, len(test.inputs), test.consensus)
+		assert.Equal(t, test.first, currentGame.record.Answers[0].Player, test.consensus)
+	}
+}
+
+func TestAskTeam_FirstAnswer(t *testing.T) {
+	// Bert answers quickest, after anna took her time
+	quizMock := &QuizMock{}
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil).Once().After(100 * time.Millisecond)
+	quizMock.On("GetUserInput", mock.Anything).Return("1", nil).Once()
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil).Once().After(100 * time.Millisecond)
+	quizMock.On("Verify", mock.Anything, mock.Anything, "4").Return(true, nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	currentGame := newGame(quizMock, &bytes.Buffer{})
+
+	answer, err := currentGame.askTeam(testQuestion, testAnswerMap, testTeams[0], ConsensusFirst)
+
+	assert.NoError(t, err)
+	assert.False(t, answer.Correct)
+	assert.Len(t, currentGame.record.Answers, 3)
+}
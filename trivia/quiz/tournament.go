@@ -0,0 +1,457 @@
+package quiz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// One player's side of a tournament match
+type TournamentSide struct {
+	Player string `json:"player,omitempty"`
+	// Set before the player sees the first question, so that nobody gets to
+	// see the questions twice
+	Started         bool          `json:"started,omitempty"`
+	Played          bool          `json:"played"`
+	GameID          string        `json:"game_id,omitempty"`
+	CorrectAnswers  int           `json:"correct_answers"`
+	NumberQuestions int           `json:"number_questions"`
+	Duration        time.Duration `json:"duration"`
+}
+
+// A head-to-head match. Both players answer the same questions with the same
+// answer order, which are fixed the first time either side plays.
+type TournamentMatch struct {
+	Round      int                 `json:"round"`
+	Sides      [2]TournamentSide   `json:"sides"`
+	Seed       int64               `json:"seed"`
+	Questions  []Question          `json:"questions,omitempty"`
+	AnswerMaps []map[string]string `json:"answer_maps,omitempty"`
+	Winner     string              `json:"winner,omitempty"`
+}
+
+// A single-elimination tournament. The matches are ordered by round, and the
+// winners of two neighbouring matches meet in the next round.
+type Tournament struct {
+	Name            string            `json:"name"`
+	Created         time.Time         `json:"created"`
+	Players         []string          `json:"players"`
+	NumberQuestions int               `json:"number_questions"`
+	Rounds          int               `json:"rounds"`
+	Matches         []TournamentMatch `json:"matches"`
+	Winner          string            `json:"winner,omitempty"`
+}
+
+// Creates the bracket for the players in seeding order. When the number of
+// players is not a power of two the best seeds get a bye in the first round.
+func NewTournament(name string, players []string, numberQuestions int, seed int64) (*Tournament, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("The tournament needs a name")
+	}
+	if len(players) < 2 {
+		return nil, fmt.Errorf("A tournament needs at least 2 players, got %d", len(players))
+	}
+	seen := map[string]bool{}
+	for _, player := range players {
+		if strings.TrimSpace(player) == "" {
+			return nil, fmt.Errorf("Player names must not be empty")
+		}
+		if seen[player] {
+			return nil, fmt.Errorf("Player '%s' is given more than once", player)
+		}
+		seen[player] = true
+	}
+
+	tournament := &Tournament{
+		Name:            name,
+		Created:         time.Now(),
+		Players:         players,
+		NumberQuestions: numberQuestions,
+	}
+	size := 1
+	for size < len(players) {
+		size *= 2
+		tournament.Rounds++
+	}
+
+	random := rand.New(rand.NewSource(seed))
+	for round := 1; round <= tournament.Rounds; round++ {
+		for position := 0; position < size>>round; position++ {
+			tournament.Matches = append(tournament.Matches, TournamentMatch{Round: round, Seed: random.Int63()})
+		}
+	}
+
+	order := bracketOrder(size)
+	for position := 0; position < size/2; position++ {
+		match := &tournament.Matches[position]
+		better, worse := order[2*position], order[2*position+1]
+		match.Sides[0].Player = players[better]
+		if worse < len(players) {
+			match.Sides[1].Player = players[worse]
+		} else {
+			tournament.advance(position, players[better])
+		}
+	}
+
+	return tournament, nil
+}
+
+// Returns the seeds, counted from 0, in the order of the first round of a
+// bracket of the given size: 1 vs 8, 4 vs 5, 2 vs 7, 3 vs 6 for 8 players.
+// Every seed plays the worst seed of its round, so the two best seeds can
+// only meet in the final, the four best in the semi-finals and so on.
+func bracketOrder(size int) []int {
+	order := []int{0}
+	for length := 2; length <= size; length *= 2 {
+		var next []int
+		for _, seed := range order {
+			next = append(next, seed, length-1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// Returns the index of the first match of the round
+func (tournament *Tournament) roundStart(round int) int {
+	for index, match := range tournament.Matches {
+		if match.Round == round {
+			return index
+		}
+	}
+	return len(tournament.Matches)
+}
+
+// Sets the winner of the match and moves them on to the next round
+func (tournament *Tournament) advance(index int, winner string) {
+	match := &tournament.Matches[index]
+	match.Winner = winner
+	if match.Round == tournament.Rounds {
+		tournament.Winner = winner
+		return
+	}
+
+	position := index - tournament.roundStart(match.Round)
+	next := &tournament.Matches[tournament.roundStart(match.Round+1)+position/2]
+	next.Sides[position%2].Player = winner
+}
+
+// Returns the index of the match the player is due to play
+func (tournament *Tournament) OpenMatch(player string) (int, error) {
+	if tournament.Winner != "" {
+		return 0, fmt.Errorf("The tournament is over, %s won", tournament.Winner)
+	}
+	if !contains(tournament.Players, player) {
+		return 0, fmt.Errorf("Player '%s' is not in the tournament", player)
+	}
+
+	for index, match := range tournament.Matches {
+		for side, current := range match.Sides {
+			if current.Player != player || match.Winner != "" {
+				continue
+			}
+			if current.Played {
+				return 0, fmt.Errorf("%s has played the match and waits for %s", player, opponentName(match, side))
+			}
+			if current.Started {
+				return 0, fmt.Errorf("%s has started the match already and cannot play it again", player)
+			}
+			if match.Sides[1-side].Player == "" {
+				return 0, fmt.Errorf("%s waits for the previous round to decide the opponent", player)
+			}
+			return index, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%s is out of the tournament", player)
+}
+
+func opponentName(match TournamentMatch, side int) string {
+	if match.Sides[1-side].Player == "" {
+		return "the opponent"
+	}
+	return match.Sides[1-side].Player
+}
+
+// Fixes the questions and answer order of the match unless already done.
+// The questions are shuffled with the seed of the match.
+func (tournament *Tournament) PrepareMatch(quiz QuizInterface, index int, configuration Configuration) error {
+	match := &tournament.Matches[index]
+	if len(match.Questions) > 0 {
+		return nil
+	}
+
+	questions, err := quiz.GetQuestions(configuration)
+	if err != nil {
+		return err
+	}
+	if len(questions) == 0 {
+		return fmt.Errorf("There are no questions for the match")
+	}
+	random := rand.New(rand.NewSource(match.Seed))
+	random.Shuffle(len(questions), func(i, j int) { questions[i], questions[j] = questions[j], questions[i] })
+	if tournament.NumberQuestions > 0 && len(questions) > tournament.NumberQuestions {
+		questions = questions[:tournament.NumberQuestions]
+	}
+
+	match.Questions = questions
+	match.AnswerMaps = nil
+	for _, question := range questions {
+		match.AnswerMaps = append(match.AnswerMaps, quiz.GetAnswerMap(question))
+	}
+
+	return nil
+}
+
+// Marks the player's side of the match as started. Call it, and save the
+// tournament, before the player sees the questions.
+func (tournament *Tournament) StartMatch(index int, player string) error {
+	match := &tournament.Matches[index]
+	for side := range match.Sides {
+		if match.Sides[side].Player != player {
+			continue
+		}
+		if match.Sides[side].Started || match.Sides[side].Played {
+			return fmt.Errorf("%s has started the match already and cannot play it again", player)
+		}
+		match.Sides[side].Started = true
+		return nil
+	}
+	return fmt.Errorf("Player '%s' is not in this match", player)
+}
+
+// Plays the player's side of a prepared match and returns the game record.
+// When the player quits or the input ends, the record of the questions
+// answered so far comes with the error, as the result of the match.
+func PlayTournamentMatch(quiz QuizInterface, stdin io.Reader, tournament *Tournament, index int, player string, options Options) (GameRecord, error) {
+	match := tournament.Matches[index]
+
+	currentGame := newGameWithOptions(quiz, stdin, options)
+	currentGame.record = GameRecord{
+		Player:  player,
+		Started: time.Now(),
+		Mode:    "tournament",
+	}
+	currentGame.renderer.Message(currentGame.localizer.Text("tournament_match",
+		tournament.Name, match.Round, match.Sides[0].Player, match.Sides[1].Player) + "\n")
+
+	for number, question := range match.Questions {
+		answerMap := match.AnswerMaps[number]
+		currentGame.renderQuestion(question, answerMap, number+1, len(match.Questions))
+
+		answer, err := currentGame.readAnswer(question, answerMap)
+		if answer.AnswerMap != nil {
+			currentGame.renderer.AnswerVerdict(verdictEvent(answer))
+			currentGame.recordAnswer(answer)
+		}
+		if err == ErrQuit {
+			quitErr := currentGame.quit(options)
+			if quitErr != nil {
+				return currentGame.finish(), quitErr
+			}
+		}
+		if err != nil {
+			return currentGame.finish(), err
+		}
+	}
+	currentGame.gameOver(quiz.FormatResult(currentGame.record.CorrectAnswers, currentGame.record.NumberQuestions), nil)
+
+	return currentGame.finish(), nil
+}
+
+// Records the player's result and decides the match once both sides have
+// played: most correct answers wins, then the shorter time, then the better
+// seed. Questions the player did not get to count as wrong.
+func (tournament *Tournament) RecordResult(index int, record GameRecord) error {
+	match := &tournament.Matches[index]
+	side := 0
+	if match.Sides[1].Player == record.Player {
+		side = 1
+	} else if match.Sides[0].Player != record.Player {
+		return fmt.Errorf("Player '%s' is not in this match", record.Player)
+	}
+	if match.Sides[side].Played {
+		return fmt.Errorf("%s has already played this match", record.Player)
+	}
+
+	numberQuestions := record.NumberQuestions
+	if len(match.Questions) > numberQuestions {
+		numberQuestions = len(match.Questions)
+	}
+	match.Sides[side] = TournamentSide{
+		Player:          record.Player,
+		Started:         true,
+		Played:          true,
+		GameID:          record.ID,
+		CorrectAnswers:  record.CorrectAnswers,
+		NumberQuestions: numberQuestions,
+		Duration:        record.Duration,
+	}
+	if !match.Sides[0].Played || !match.Sides[1].Played {
+		return nil
+	}
+
+	first, second := match.Sides[0], match.Sides[1]
+	winner := first.Player
+	if second.CorrectAnswers > first.CorrectAnswers ||
+		second.CorrectAnswers == first.CorrectAnswers && second.Duration < first.Duration ||
+		second.CorrectAnswers == first.CorrectAnswers && second.Duration == first.Duration &&
+			tournament.seed(second.Player) < tournament.seed(first.Player) {
+		winner = second.Player
+	}
+	tournament.advance(index, winner)
+
+	return nil
+}
+
+// Returns the seeding position of the player, 0 for the best seed
+func (tournament *Tournament) seed(player string) int {
+	for index, current := range tournament.Players {
+		if current == player {
+			return index
+		}
+	}
+	return len(tournament.Players)
+}
+
+// Returns the file holding the tournament
+func TournamentFile(dataDir string, name string) string {
+	return filepath.Join(dataDir, "tournaments", SafeFileName(name)+".json")
+}
+
+func ReadTournament(tournamentFile string) (*Tournament, error) {
+	file, err := ioutil.ReadFile(tournamentFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var tournament Tournament
+	err = json.Unmarshal(file, &tournament)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse tournament %s: %s", tournamentFile, err.Error())
+	}
+
+	return &tournament, nil
+}
+
+func WriteTournament(tournamentFile string, tournament *Tournament) error {
+	data, err := json.MarshalIndent(tournament, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(tournamentFile, data)
+}
+
+// Returns all tournaments kept in the data directory, sorted by name
+func ListTournaments(dataDir string) ([]*Tournament, error) {
+	files, err := filepath.Glob(filepath.Join(dataDir, "tournaments", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var tournaments []*Tournament
+	for _, file := range files {
+		tournament, err := ReadTournament(file)
+		if err != nil {
+			return nil, err
+		}
+		tournaments = append(tournaments, tournament)
+	}
+	sort.Slice(tournaments, func(i, j int) bool { return tournaments[i].Name < tournaments[j].Name })
+
+	return tournaments, nil
+}
+
+func FormatTournamentList(tournaments []*Tournament, localizer *Localizer) string {
+	if len(tournaments) == 0 {
+		return localizer.Text("no_tournaments")
+	}
+
+	var builder strings.Builder
+	for _, tournament := range tournaments {
+		status := localizer.Text("tournament_in_progress")
+		if tournament.Winner != "" {
+			status = localizer.Text("tournament_won_by", tournament.Winner)
+		}
+		builder.WriteString(localizer.Text("tournament_list_entry", tournament.Name, len(tournament.Players), status) + "\n")
+	}
+
+	return builder.String()
+}
+
+func FormatBracket(tournament *Tournament, localizer *Localizer) string {
+	var builder strings.Builder
+	builder.WriteString(localizer.Text("tournament", tournament.Name, len(tournament.Players)))
+	if tournament.NumberQuestions > 0 {
+		builder.WriteString(localizer.Plural("questions_per_match", tournament.NumberQuestions, tournament.NumberQuestions))
+	}
+	builder.WriteString("\n")
+
+	for round := 1; round <= tournament.Rounds; round++ {
+		builder.WriteString(fmt.Sprintf("\n%s\n", roundTitle(round, tournament.Rounds, localizer)))
+		for _, match := range tournament.Matches {
+			if match.Round != round {
+				continue
+			}
+			builder.WriteString(localizer.Text("match",
+				formatSide(match.Sides[0]), formatSide(match.Sides[1]), matchStatus(match, localizer)) + "\n")
+		}
+	}
+
+	if tournament.Winner != "" {
+		builder.WriteString("\n" + localizer.Text("tournament_winner", tournament.Winner) + "\n")
+	}
+
+	return builder.String()
+}
+
+func roundTitle(round int, rounds int, localizer *Localizer) string {
+	switch rounds - round {
+	case 0:
+		return localizer.Text("round_final")
+	case 1:
+		return localizer.Text("round_semi_finals")
+	case 2:
+		return localizer.Text("round_quarter_finals")
+	}
+	return localizer.Text("round", round)
+}
+
+func formatSide(side TournamentSide) string {
+	if side.Player == "" {
+		return "?"
+	}
+	if !side.Played {
+		return side.Player
+	}
+	return fmt.Sprintf("%s (%d/%d, %s)", side.Player, side.CorrectAnswers, side.NumberQuestions, side.Duration.Round(time.Second))
+}
+
+func matchStatus(match TournamentMatch, localizer *Localizer) string {
+	if match.Winner != "" {
+		if match.Sides[1].Player == "" || match.Sides[0].Player == "" {
+			return localizer.Text("match_bye", match.Winner)
+		}
+		return localizer.Text("match_won", match.Winner)
+	}
+
+	var waiting []string
+	for _, side := range match.Sides {
+		if side.Player != "" && !side.Played {
+			waiting = append(waiting, side.Player)
+		}
+	}
+	if match.Sides[0].Player == "" || match.Sides[1].Player == "" {
+		return localizer.Text("match_waiting_round")
+	}
+	if len(waiting) == 2 {
+		return localizer.Text("match_waiting_both", waiting[0], waiting[1])
+	}
+	return localizer.Text("match_waiting", strings.Join(waiting, ", "))
+}
@@ -0,0 +1,213 @@
+package quiz
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewTournament(t *testing.T) {
+	tournament, err := NewTournament("office", []string{"anna", "bert", "cecilia"}, 5, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, tournament.Rounds)
+	assert.Len(t, tournament.Matches, 3)
+
+	// The best seed gets a bye and waits in the final
+	assert.Equal(t, "anna", tournament.Matches[0].Winner)
+	assert.Equal(t, "bert", tournament.Matches[1].Sides[0].Player)
+	assert.Equal(t, "cecilia", tournament.Matches[1].Sides[1].Player)
+	assert.Equal(t, "anna", tournament.Matches[2].Sides[0].Player)
+	assert.Equal(t, "", tournament.Matches[2].Sides[1].Player)
+	assert.NotEqual(t, tournament.Matches[1].Seed, tournament.Matches[2].Seed)
+
+	again, _ := NewTournament("office", []string{"anna", "bert", "cecilia"}, 5, 1)
+	assert.Equal(t, tournament.Matches, again.Matches)
+
+	_, err = NewTournament("office", []string{"anna"}, 5, 1)
+	assert.EqualError(t, err, "A tournament needs at least 2 players, got 1")
+	_, err = NewTournament("office", []string{"anna", "anna"}, 5, 1)
+	assert.EqualError(t, err, "Player 'anna' is given more than once")
+	_, err = NewTournament(" ", []string{"anna", "bert"}, 5, 1)
+	assert.Error(t, err)
+}
+
+func TestNewTournament_BracketOrder(t *testing.T) {
+	assert.Equal(t, []int{0, 7, 3, 4, 1, 6, 2, 5}, bracketOrder(8))
+
+	players := []string{"s1", "s2", "s3", "s4", "s5", "s6", "s7", "s8"}
+	tournament, _ := NewTournament("office", players, 5, 1)
+	var pairs [][2]string
+	for _, match := range tournament.Matches[:4] {
+		pairs = append(pairs, [2]string{match.Sides[0].Player, match.Sides[1].Player})
+	}
+	assert.Equal(t, [][2]string{{"s1", "s8"}, {"s4", "s5"}, {"s2", "s7"}, {"s3", "s6"}}, pairs)
+
+	// With the better seed winning every match, the two best meet in the final
+	for index := 0; index < len(tournament.Matches)-1; index++ {
+		tournament.advance(index, tournament.Matches[index].Sides[0].Player)
+	}
+	final := tournament.Matches[len(tournament.Matches)-1]
+	assert.Equal(t, [2]string{"s1", "s2"}, [2]string{final.Sides[0].Player, final.Sides[1].Player})
+
+	// Byes go to the best seeds
+	tournament, _ = NewTournament("office", players[:6], 5, 1)
+	assert.Equal(t, "s1", tournament.Matches[0].Winner)
+	assert.Equal(t, "s2", tournament.Matches[2].Winner)
+}
+
+func TestTournamentOpenMatch(t *testing.T) {
+	tournament, _ := NewTournament("office", []string{"anna", "bert", "cecilia"}, 5, 1)
+
+	index, err := tournament.OpenMatch("bert")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, index)
+
+	_, err = tournament.OpenMatch("anna")
+	assert.EqualError(t, err, "anna waits for the previous round to decide the opponent")
+	_, err = tournament.OpenMatch("david")
+	assert.EqualError(t, err, "Player 'david' is not in the tournament")
+}
+
+func TestTournamentRecordResult(t *testing.T) {
+	tournament, _ := NewTournament("office", []string{"anna", "bert", "cecilia", "david"}, 5, 1)
+	assert.Equal(t, "david", tournament.Matches[0].Sides[1].Player)
+
+	assert.NoError(t, tournament.RecordResult(0, GameRecord{Player: "david", CorrectAnswers: 3, Duration: time.Second}))
+	_, err := tournament.OpenMatch("david")
+	assert.EqualError(t, err, "david has played the match and waits for anna")
+	assert.EqualError(t, tournament.RecordResult(0, GameRecord{Player: "david"}), "david has already played this match")
+	assert.EqualError(t, tournament.RecordResult(0, GameRecord{Player: "bert"}), "Player 'bert' is not in this match")
+
+	// Same score, the faster player wins
+	assert.NoError(t, tournament.RecordResult(0, GameRecord{Player: "anna", CorrectAnswers: 3, Duration: 2 * time.Second}))
+	assert.Equal(t, "david", tournament.Matches[0].Winner)
+	assert.Equal(t, "david", tournament.Matches[2].Sides[0].Player)
+	_, err = tournament.OpenMatch("anna")
+	assert.EqualError(t, err, "anna is out of the tournament")
+
+	assert.NoError(t, tournament.RecordResult(1, GameRecord{Player: "bert", CorrectAnswers: 4}))
+	assert.NoError(t, tournament.RecordResult(1, GameRecord{Player: "cecilia", CorrectAnswers: 2}))
+	// A full tie goes to the better seed, bert, although david is on the first side
+	assert.Equal(t, "david", tournament.Matches[2].Sides[0].Player)
+	assert.NoError(t, tournament.RecordResult(2, GameRecord{Player: "bert", CorrectAnswers: 1}))
+	assert.NoError(t, tournament.RecordResult(2, GameRecord{Player: "david", CorrectAnswers: 1}))
+	assert.Equal(t, "bert", tournament.Winner)
+
+	_, err = tournament.OpenMatch("bert")
+	assert.EqualError(t, err, "The tournament is over, bert won")
+}
+
+func TestTournamentStartMatch(t *testing.T) {
+	tournament, _ := NewTournament("office", []string{"anna", "bert"}, 5, 1)
+	tournament.Matches[0].Questions = []Question{testQuestion, testQuestion2}
+
+	assert.NoError(t, tournament.StartMatch(0, "bert"))
+	assert.EqualError(t, tournament.StartMatch(0, "bert"), "bert has started the match already and cannot play it again")
+	assert.EqualError(t, tournament.StartMatch(0, "cecilia"), "Player 'cecilia' is not in this match")
+	_, err := tournament.OpenMatch("bert")
+	assert.EqualError(t, err, "bert has started the match already and cannot play it again")
+
+	// Bert gave up after one question, the other counts as wrong
+	assert.NoError(t, tournament.RecordResult(0, GameRecord{Player: "bert", CorrectAnswers: 1, NumberQuestions: 1}))
+	assert.Equal(t, 2, tournament.Matches[0].Sides[1].NumberQuestions)
+	_, err = tournament.OpenMatch("bert")
+	assert.EqualError(t, err, "bert has played the match and waits for anna")
+}
+
+func TestPlayTournamentMatch(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("GetQuestions", mock.Anything).Return([]Question{testQuestion, testQuestion2, testQuestion}, nil)
+	quizMock.On("GetAnswerMap", testQuestion).Return(testAnswerMap)
+	quizMock.On("GetAnswerMap", testQuestion2).Return(testAnswerMap2)
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil)
+	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	quizMock.On("FormatResult", mock.Anything, mock.Anything).Return("Formatted result")
+
+	tournament, _ := NewTournament("office", []string{"anna", "bert"}, 2, 1)
+	assert.NoError(t, tournament.PrepareMatch(quizMock, 0, testConfiguration))
+	questions := tournament.Matches[0].Questions
+	assert.Len(t, questions, 2)
+	assert.Len(t, tournament.Matches[0].AnswerMaps, 2)
+
+	// Both sides get the questions fixed by the first preparation
+	assert.NoError(t, tournament.PrepareMatch(quizMock, 0, testConfiguration))
+	quizMock.AssertNumberOfCalls(t, "GetQuestions", 1)
+	assert.Equal(t, questions, tournament.Matches[0].Questions)
+
+	var stdin bytes.Buffer
+	record, err := PlayTournamentMatch(quizMock, &stdin, tournament, 0, "bert", Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "bert", record.Player)
+	assert.Equal(t, "tournament", record.Mode)
+	assert.Equal(t, 2, record.CorrectAnswers)
+	assert.NotEmpty(t, record.ID)
+	quizMock.AssertCalled(t, "Verify", questions[0], tournament.Matches[0].AnswerMaps[0], "4")
+}
+
+func TestPlayTournamentMatch_Quit(t *testing.T) {
+	quizMock := &QuizMock{}
+	quizMock.On("FormatQuestion", mock.Anything, mock.Anything).Return("Formatted question")
+	quizMock.On("GetUserInput", mock.Anything).Return("4", nil).Once()
+	quizMock.On("GetUserInput", mock.Anything).Return("quit", nil).Once()
+	quizMock.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	quizMock.On("FormatResult", 1, 1).Return("Formatted result")
+
+	tournament, _ := NewTournament("office", []string{"anna", "bert"}, 2, 1)
+	tournament.Matches[0].Questions = []Question{testQuestion, testQuestion2}
+	tournament.Matches[0].AnswerMaps = []map[string]string{testAnswerMap, testAnswerMap2}
+
+	var stdin bytes.Buffer
+	var out bytes.Buffer
+	record, err := PlayTournamentMatch(quizMock, &stdin, tournament, 0, "bert", Options{Renderer: NewPlainRenderer(&out, nil)})
+
+	// The answers so far are the result of the match
+	assert.Equal(t, ErrQuit, err)
+	assert.Equal(t, 1, record.CorrectAnswers)
+	assert.Equal(t, 1, record.NumberQuestions)
+	assert.Contains(t, out.String(), "Formatted result")
+}
+
+func TestWriteAndListTournaments(t *testing.T) {
+	dataDir := t.TempDir()
+	tournaments, err := ListTournaments(dataDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "No tournaments.", FormatTournamentList(tournaments, nil))
+
+	tournament, _ := NewTournament("Office cup", []string{"anna", "bert"}, 5, 1)
+	file := TournamentFile(dataDir, tournament.Name)
+	assert.Equal(t, filepath.Join(dataDir, "tournaments", "Office_cup.json"), file)
+	assert.NoError(t, WriteTournament(file, tournament))
+
+	read, err := ReadTournament(file)
+	assert.NoError(t, err)
+	assert.Equal(t, tournament.Matches, read.Matches)
+
+	tournaments, _ = ListTournaments(dataDir)
+	assert.Equal(t, "Office cup           2 players, in progress\n", FormatTournamentList(tournaments, nil))
+}
+
+func TestFormatBracket(t *testing.T) {
+	tournament, _ := NewTournament("office", []string{"anna", "bert", "cecilia"}, 5, 1)
+	tournament.RecordResult(1, GameRecord{Player: "bert", CorrectAnswers: 4, NumberQuestions: 5, Duration: 12 * time.Second})
+
+	expected := "Tournament office: 3 players, 5 questions per match\n" +
+		"\nSemi-finals\n" +
+		"  anna vs ?: anna has a bye\n" +
+		"  bert (4/5, 12s) vs cecilia: waiting for cecilia\n" +
+		"\nFinal\n" +
+		"  anna vs ?: waiting for the previous round\n"
+	assert.Equal(t, expected, FormatBracket(tournament, nil))
+
+	expected = "Turnering office: 3 spelare, 5 frågor per match\n" +
+		"\nSemifinaler\n" +
+		"  anna mot ?: anna går direkt vidare\n" +
+		"  bert (4/5, 12s) mot cecilia: väntar på cecilia\n" +
+		"\nFinal\n" +
+		"  anna mot ?: väntar på föregående omgång\n"
+	assert.Equal(t, expected, FormatBracket(tournament, swedish(t)))
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"trivia/quiz"
+)
+
+func replay(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: trivia replay SESSION_FILE")
+	}
+
+	recording, err := quiz.ReadRecording(args[0])
+	if err != nil {
+		return err
+	}
+
+	divergences, err := quiz.Replay(&quiz.Quiz{}, recording)
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	fmt.Print(quiz.FormatDivergences(divergences, recording))
+	if len(divergences) > 0 {
+		return fmt.Errorf("The replay of %s diverged", args[0])
+	}
+	fmt.Println()
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"trivia/quiz"
+)
+
+func resume(args []string, stdin io.Reader) error {
+	flags := flag.NewFlagSet("resume", flag.ExitOnError)
+	player := flags.String("player", defaultPlayer(), "Name of the player")
+	output := flags.String("output", quiz.RendererAuto, "How to print the game: auto, color, nocolor, plain, accessible or jsonl")
+	accessible := flags.Bool("accessible", false, "Linear output for screen readers (default: from the configuration)")
+	language := flags.String("lang", "", "Language of the game, e.g. sv (default: from LANG)")
+	flags.Parse(args)
+
+	configuration, err := (&quiz.Quiz{}).ReadConfigurationFromYAML(configFile)
+	if err != nil {
+		return err
+	}
+	dataDir := quiz.DataDir(configuration)
+	saveFile := quiz.SavedGameFile(dataDir, *player)
+	saved, err := quiz.ReadSavedGame(saveFile)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("There is no saved game for %s", *player)
+	}
+	if err != nil {
+		return err
+	}
+
+	localizer, err := quiz.SelectLocalizer(*language)
+	if err != nil {
+		return err
+	}
+	// Continue with the labels and the answer orders of the game as it was
+	// played
+	quizGame, _ := newQuiz(saved.Record.Seed)
+	quizGame.SetLocalizer(localizer)
+	labels := saved.Labels
+	if labels == "" {
+		labels = saved.Record.Configuration.Labels
+	}
+	err = quizGame.SetLabels(labels)
+	if err != nil {
+		return err
+	}
+	quizGame.SkipDraws(saved.Draws)
+	if accessibleMode(flags, *accessible, configuration) {
+		*output = quiz.RendererAccessible
+		quizGame.SetAccessible(true)
+	}
+
+	options := quiz.Options{
+		Player:    *player,
+		Localizer: localizer,
+		History:   quiz.NewHistory(dataDir),
+		SaveFile:  saveFile,
+		TimeLimit: saved.TimeLimit,
+		Labels:    labels,
+	}
+	options.Renderer, err = quiz.NewRendererByName(*output, os.Stdout, localizer)
+	if err != nil {
+		return err
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	options.Interrupt = interrupt
+
+	return quiz.Resume(quizGame, stdin, saved, options)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+	"trivia/network"
+	"trivia/quiz"
+)
+
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	address := flags.String("addr", ":7777", "Address to listen on")
+	players := flags.Int("players", 2, "Number of players to wait for before the game starts")
+	timeLimit := flags.Duration("time-limit", 20*time.Second, "Time to answer each question")
+	flags.Parse(args)
+
+	quizGame, _ := newQuiz(0)
+	configuration, err := quizGame.ReadConfigurationFromYAML(configFile)
+	if err != nil {
+		return err
+	}
+	questions, err := quizGame.GetQuestions(configuration)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", *address)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	match := quiz.NewMatch(quizGame, questions, *timeLimit)
+	server := network.NewServer(match)
+	go server.Serve(listener)
+
+	fmt.Printf("Serving on %s, waiting for %d players to join...\n", listener.Addr(), *players)
+	match.WaitForPlayers(*players, nil)
+	fmt.Printf("Starting the game with %s\n", strings.Join(match.Players(), ", "))
+
+	scores := match.Play()
+	match.Close()
+	server.Wait()
+	fmt.Println(quiz.FormatRanking(scores, nil))
+
+	return nil
+}
+
+func join(args []string, stdin io.Reader) error {
+	flags := flag.NewFlagSet("join", flag.ExitOnError)
+	player := flags.String("player", defaultPlayer(), "Name of the player")
+	accessible := flags.Bool("accessible", false, "Linear output for screen readers (default: from the configuration)")
+	language := flags.String("lang", "", "Language of the game, e.g. sv (default: from LANG)")
+	flags.Usage = func() {
+		fmt.Println("Usage: trivia join HOST:PORT [-player NAME] [-accessible] [-lang LANGUAGE]")
+		flags.PrintDefaults()
+	}
+
+	// Allow the address both before and after the flags
+	var address string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		address = args[0]
+		args = args[1:]
+	}
+	flags.Parse(args)
+	if address == "" {
+		address = flags.Arg(0)
+	}
+	if address == "" {
+		flags.Usage()
+		return fmt.Errorf("Missing server address")
+	}
+
+	localizer, err := quiz.SelectLocalizer(*language)
+	if err != nil {
+		return err
+	}
+
+	return network.Join(address, *player, joinAccessible(flags, *accessible), localizer, stdin, os.Stdout)
+}
+
+// Returns whether to join in the accessible mode, as given with -accessible
+// or else as configured. A client needs no configuration, without one it is
+// not accessible.
+func joinAccessible(flags *flag.FlagSet, accessible bool) bool {
+	if flagGiven(flags, "accessible") {
+		return accessible
+	}
+	if _, err := os.Stat(configFile); err != nil {
+		return false
+	}
+	configuration, err := (&quiz.Quiz{}).ReadConfigurationFromYAML(configFile)
+	if err != nil {
+		return false
+	}
+	return configuration.Accessibility.Enabled
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"trivia/quiz"
+)
+
+func stats(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	player := flags.String("player", "", "Only include the games of this player")
+	asJSON := flags.Bool("json", false, "Print the statistics as JSON")
+	flags.Parse(args)
+
+	configuration, err := (&quiz.Quiz{}).ReadConfigurationFromYAML(configFile)
+	if err != nil {
+		return err
+	}
+	records, err := quiz.NewHistory(quiz.DataDir(configuration)).PlayerRecords(*player)
+	if err != nil {
+		return err
+	}
+
+	statistics := quiz.Stats(records, *player)
+	if *asJSON {
+		data, err := json.MarshalIndent(statistics, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Print(quiz.FormatStats(statistics))
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+	"trivia/quiz"
+)
+
+func study(args []string, stdin io.Reader) error {
+	flags := flag.NewFlagSet("study", flag.ExitOnError)
+	decks := flags.String("deck", "", "Comma separated deck files (default: decks from the configuration)")
+	player := flags.String("player", defaultPlayer(), "Name of the player")
+	dueOnly := flags.Bool("due", false, "Only show the number of due cards")
+	accessible := flags.Bool("accessible", false, "Linear output for screen readers (default: from the configuration)")
+	language := flags.String("lang", "", "Language of the study session, e.g. sv (default: from LANG)")
+	flags.Parse(args)
+
+	localizer, err := quiz.SelectLocalizer(*language)
+	if err != nil {
+		return err
+	}
+	quizGame, _ := newQuiz(0)
+	quizGame.SetLocalizer(localizer)
+	configuration, err := quizGame.ReadConfigurationFromYAML(configFile)
+	if err != nil {
+		return err
+	}
+
+	deckFiles := configuration.Decks
+	if *decks != "" {
+		deckFiles = strings.Split(*decks, ",")
+	}
+	if len(deckFiles) == 0 {
+		return fmt.Errorf("No decks given, use -deck or 'decks' in %s", configFile)
+	}
+
+	questions, err := quiz.ReadDecks(deckFiles)
+	if err != nil {
+		return err
+	}
+
+	stateFile := quiz.StudyStateFile(quiz.DataDir(configuration), *player)
+	state, err := quiz.ReadStudyState(stateFile, *player)
+	if err != nil {
+		return err
+	}
+
+	today := time.Now()
+	due := state.DueQuestions(questions, today)
+	fmt.Println(quiz.FormatDue(len(due), len(questions), localizer))
+	if *dueOnly || len(due) == 0 {
+		return nil
+	}
+
+	options := quiz.Options{Localizer: localizer}
+	if accessibleMode(flags, *accessible, configuration) {
+		quizGame.SetAccessible(true)
+		options.Renderer = quiz.NewAccessibleRenderer(os.Stdout, localizer)
+	}
+
+	// Ctrl-C ends the study session like the quit command
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	options.Interrupt = interrupt
+
+	correctAnswers, studyErr := quiz.Study(quizGame, stdin, questions, state, today, options)
+	err = quiz.WriteStudyState(stateFile, state)
+	if err != nil {
+		return err
+	}
+	// The cards reviewed before quitting keep their new schedule
+	if studyErr == quiz.ErrQuit {
+		return nil
+	}
+	if studyErr != nil {
+		return studyErr
+	}
+	fmt.Println(quizGame.FormatResult(correctAnswers, len(due)))
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+	"trivia/quiz"
+)
+
+const tournamentUsage string = `Usage: trivia tournament create NAME -players NAME,NAME,... [-questions N] [-lang LANG]
+       trivia tournament list [-lang LANG]
+       trivia tournament show NAME [-lang LANG]
+       trivia tournament play NAME [-player NAME] [-lang LANG] [-accessible]`
+
+func tournament(args []string, stdin io.Reader) error {
+	if len(args) == 0 {
+		return fmt.Errorf(tournamentUsage)
+	}
+
+	quizGame, _ := newQuiz(0)
+	configuration, err := quizGame.ReadConfigurationFromYAML(configFile)
+	if err != nil {
+		return err
+	}
+	dataDir := quiz.DataDir(configuration)
+
+	command, args := args[0], args[1:]
+
+	// The tournament name may be given before or after the flags
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name = args[0]
+		args = args[1:]
+	}
+
+	flags := flag.NewFlagSet("tournament "+command, flag.ExitOnError)
+	players := flags.String("players", "", "Comma separated players in seeding order, best first")
+	numberQuestions := flags.Int("questions", 10, "Number of questions per match (0 uses all questions)")
+	player := flags.String("player", defaultPlayer(), "Name of the player")
+	language := flags.String("lang", "", "Language of the tournament, e.g. sv (default: from LANG)")
+	accessible := flags.Bool("accessible", false, "Linear output for screen readers (default: from the configuration)")
+	flags.Parse(args)
+
+	localizer, err := quiz.SelectLocalizer(*language)
+	if err != nil {
+		return err
+	}
+	quizGame.SetLocalizer(localizer)
+
+	if command == "list" {
+		tournaments, err := quiz.ListTournaments(dataDir)
+		if err != nil {
+			return err
+		}
+		fmt.Print(quiz.FormatTournamentList(tournaments, localizer))
+		return nil
+	}
+
+	if name == "" {
+		name = flags.Arg(0)
+	}
+	if name == "" {
+		return fmt.Errorf("Missing tournament name\n%s", tournamentUsage)
+	}
+	tournamentFile := quiz.TournamentFile(dataDir, name)
+
+	switch command {
+	case "create":
+		return createTournament(tournamentFile, name, *players, *numberQuestions, dataDir, localizer)
+	case "show":
+		current, err := readTournament(tournamentFile, name)
+		if err != nil {
+			return err
+		}
+		fmt.Print(quiz.FormatBracket(current, localizer))
+		return nil
+	case "play":
+		options := quiz.Options{Localizer: localizer}
+		if accessibleMode(flags, *accessible, configuration) {
+			quizGame.SetAccessible(true)
+			options.Renderer = quiz.NewAccessibleRenderer(os.Stdout, localizer)
+		}
+		return playTournament(quizGame, stdin, tournamentFile, name, *player, configuration, options)
+	}
+
+	return fmt.Errorf("Unknown tournament command '%s'\n%s", command, tournamentUsage)
+}
+
+func createTournament(tournamentFile string, name string, players string, numberQuestions int, dataDir string, localizer *quiz.Localizer) error {
+	if _, err := os.Stat(tournamentFile); err == nil {
+		return fmt.Errorf("Tournament '%s' already exists", name)
+	}
+	if players == "" {
+		return fmt.Errorf("Missing -players")
+	}
+
+	created, err := quiz.NewTournament(name, strings.Split(players, ","), numberQuestions, time.Now().UnixNano())
+	if err != nil {
+		return err
+	}
+	profiles := quiz.NewProfiles(dataDir)
+	for _, player := range created.Players {
+		_, err = profiles.Ensure(player)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = quiz.WriteTournament(tournamentFile, created)
+	if err != nil {
+		return err
+	}
+	fmt.Print(quiz.FormatBracket(created, localizer))
+
+	return nil
+}
+
+func playTournament(quizGame *quiz.Quiz, stdin io.Reader, tournamentFile string, name string, player string, configuration quiz.Configuration, options quiz.Options) error {
+	current, err := readTournament(tournamentFile, name)
+	if err != nil {
+		return err
+	}
+	index, err := current.OpenMatch(player)
+	if err != nil {
+		return err
+	}
+
+	// Keep the questions of the match so that the opponent gets the same
+	// ones, and note that the player has seen them
+	err = current.PrepareMatch(quizGame, index, configuration)
+	if err != nil {
+		return err
+	}
+	err = current.StartMatch(index, player)
+	if err != nil {
+		return err
+	}
+	err = quiz.WriteTournament(tournamentFile, current)
+	if err != nil {
+		return err
+	}
+
+	// Ctrl-C ends the match like the quit command, with the score so far
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	options.Interrupt = interrupt
+
+	// A match given up counts with the answers given so far
+	record, playErr := quiz.PlayTournamentMatch(quizGame, stdin, current, index, player, options)
+	record.Configuration = configuration
+	err = current.RecordResult(index, record)
+	if err != nil {
+		return err
+	}
+	err = quiz.WriteTournament(tournamentFile, current)
+	if err != nil {
+		return err
+	}
+	err = quiz.NewHistory(quiz.DataDir(configuration)).Append(record)
+	if err != nil {
+		return err
+	}
+	if playErr != nil && playErr != quiz.ErrQuit {
+		return playErr
+	}
+
+	fmt.Print(quiz.FormatBracket(current, options.Localizer))
+
+	return nil
+}
+
+func readTournament(tournamentFile string, name string) (*quiz.Tournament, error) {
+	current, err := quiz.ReadTournament(tournamentFile)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("No tournament named '%s'", name)
+	}
+	return current, err
+}
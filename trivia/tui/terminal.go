@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const hideCursor string = "\033[?25l"
+const showCursor string = "\033[?25h"
+
+// Returns whether the file is a terminal rather than a pipe or a file
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Switches the terminal to reading single key presses without echo, with
+// Ctrl-C delivered as a key, and hides the cursor. Returns a function that
+// restores the terminal.
+func Open(terminal *os.File, out *os.File) (func(), error) {
+	if !IsTerminal(terminal) {
+		return nil, fmt.Errorf("The full-screen mode needs a terminal, play without -tui in scripts")
+	}
+
+	state, err := stty(terminal, "-g")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the terminal settings: %s", err.Error())
+	}
+	_, err = stty(terminal, "-icanon", "-echo", "-isig", "min", "1")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to change the terminal settings: %s", err.Error())
+	}
+	fmt.Fprint(out, hideCursor)
+
+	return func() {
+		fmt.Fprint(out, showCursor)
+		stty(terminal, strings.TrimSpace(state))
+	}, nil
+}
+
+func stty(terminal *os.File, args ...string) (string, error) {
+	command := exec.Command("stty", args...)
+	command.Stdin = terminal
+	output, err := command.Output()
+	return string(output), err
+}
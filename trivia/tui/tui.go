@@ -0,0 +1,364 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"trivia/quiz"
+)
+
+const clearScreen string = "\033[2J\033[H"
+const colorReset string = "\033[0m"
+const colorRed string = "\033[31m"
+const colorGreen string = "\033[32m"
+const colorBold string = "\033[1m"
+const colorReverse string = "\033[7m"
+
+const progressWidth int = 30
+const tickInterval time.Duration = 100 * time.Millisecond
+
+type key int
+
+const (
+	keyUp key = iota
+	keyDown
+	keyEnter
+	keyDigit
+	keyQuit
+	keyOther
+)
+
+type keyPress struct {
+	key   key
+	digit int
+}
+
+// A question as it was answered, for the results screen
+type answered struct {
+	question string
+	answer   string
+	correct  bool
+}
+
+// Full-screen front-end for a game. Wraps a quiz so that the game asks its
+// questions as a menu chosen with the arrow keys or numbers, with a progress
+// bar, a countdown when there is a time limit and a results screen.
+type UI struct {
+	quiz.QuizInterface
+	out       io.Writer
+	timeLimit time.Duration
+	localizer *quiz.Localizer
+	keys      chan keyPress
+
+	total     int
+	number    int
+	question  quiz.Question
+	answerMap map[string]string
+	selected  int
+	timedOut  bool
+	// Quit pressed on the time-out screen, the game quits at the next question
+	quitting bool
+	answers  []answered
+}
+
+// Creates the front-end drawing on out in the language of the localizer. A
+// time limit of zero means no limit.
+func New(quizGame quiz.QuizInterface, out io.Writer, timeLimit time.Duration, localizer *quiz.Localizer) *UI {
+	return &UI{QuizInterface: quizGame, out: out, timeLimit: timeLimit, localizer: localizer}
+}
+
+func (ui *UI) GetQuestions(configuration quiz.Configuration) ([]quiz.Question, error) {
+	questions, err := ui.QuizInterface.GetQuestions(configuration)
+	ui.total += len(questions)
+	return questions, err
+}
+
+// Keeps the question for the screen drawn when the answer is read; the game
+// itself prints nothing but the question number
+func (ui *UI) FormatQuestion(question quiz.Question, answerMap map[string]string) string {
+	ui.number++
+	if ui.number > ui.total {
+		ui.total = ui.number
+	}
+	ui.question = question
+	ui.answerMap = answerMap
+	return ""
+}
+
+// Tells the shuffles of the wrapped quiz, so that a game quit in the
+// full-screen mode is resumed with the same answer orders
+func (ui *UI) Draws() []int {
+	if counter, ok := ui.QuizInterface.(interface{ Draws() []int }); ok {
+		return counter.Draws()
+	}
+	return nil
+}
+
+// Lets the player move through the answers and returns the chosen one. When
+// the time is up it shows the verdict and returns quiz.ErrTimeUp, so that the
+// game records the question as timed out.
+func (ui *UI) GetUserInput(stdin io.Reader) (string, error) {
+	if ui.quitting {
+		return "", quiz.ErrQuit
+	}
+	ui.startKeys(stdin)
+	ui.selected = 0
+	ui.timedOut = false
+	labels := quiz.SortedLabels(ui.answerMap)
+
+	deadline := time.Now().Add(ui.timeLimit)
+	var tick <-chan time.Time
+	if ui.timeLimit > 0 {
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	shown := -1
+	for {
+		left := secondsLeft(deadline)
+		if left != shown || ui.timeLimit == 0 {
+			fmt.Fprint(ui.out, ui.questionScreen(left))
+			shown = left
+		}
+
+		select {
+		case press, ok := <-ui.keys:
+			if !ok {
+				return "", io.EOF
+			}
+			switch press.key {
+			case keyUp:
+				ui.selected = (ui.selected + len(labels) - 1) % len(labels)
+			case keyDown:
+				ui.selected = (ui.selected + 1) % len(labels)
+			case keyEnter:
+				return labels[ui.selected], nil
+			case keyDigit:
+				if press.digit >= 1 && press.digit <= len(labels) {
+					return labels[press.digit-1], nil
+				}
+			case keyQuit:
+				return "", quiz.ErrQuit
+			}
+			shown = -1
+		case <-tick:
+			if !time.Now().Before(deadline) {
+				ui.timedOut = true
+				ui.quitting = ui.showVerdict("", false)
+				return "", quiz.ErrTimeUp
+			}
+		}
+	}
+}
+
+// Verifies the answer, shows the verdict and waits for a key
+func (ui *UI) Verify(question quiz.Question, answerMap map[string]string, userInput string) (bool, error) {
+	correct, err := ui.QuizInterface.Verify(question, answerMap, userInput)
+	if err != nil {
+		return false, err
+	}
+
+	if ui.showVerdict(userInput, correct) {
+		return correct, quiz.ErrQuit
+	}
+	return correct, nil
+}
+
+// Keeps the answer for the results screen, shows the verdict and waits for a
+// key. Returns whether the key was quit.
+func (ui *UI) showVerdict(userInput string, correct bool) bool {
+	ui.answers = append(ui.answers, answered{question: ui.question.Question, answer: ui.answerMap[userInput], correct: correct})
+	fmt.Fprint(ui.out, ui.verdictScreen(userInput, correct))
+	press, ok := <-ui.keys
+	return ok && press.key == keyQuit
+}
+
+func (ui *UI) FormatResult(numberCorrectAnswers int, numberQuestions int) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%s %s%s\n\n", colorBold, ui.localizer.Text("tui_results"), colorReset))
+	builder.WriteString(fmt.Sprintf(" %s\n", ui.QuizInterface.FormatResult(numberCorrectAnswers, numberQuestions)))
+	builder.WriteString(fmt.Sprintf(" %s\n\n", progressBar(numberCorrectAnswers, numberQuestions)))
+
+	for _, answer := range ui.answers {
+		if answer.correct {
+			builder.WriteString(fmt.Sprintf(" %s✔%s %s\n", colorGreen, colorReset, answer.question))
+		} else if answer.answer == "" {
+			builder.WriteString(fmt.Sprintf(" %s✘%s %s\n", colorRed, colorReset, ui.localizer.Text("tui_no_answer", answer.question)))
+		} else {
+			builder.WriteString(fmt.Sprintf(" %s✘%s %s\n", colorRed, colorReset, ui.localizer.Text("tui_you_answered", answer.question, answer.answer)))
+		}
+	}
+
+	return builder.String()
+}
+
+// The UI is the renderer of its game as well: it draws the questions and
+// verdicts on screens of its own when the answer is read and verified, so the
+// game events draw nothing and only the results and messages are written.
+func (ui *UI) QuestionShown(event quiz.QuestionEvent) {}
+
+func (ui *UI) AnswerVerdict(event quiz.VerdictEvent) {}
+
+func (ui *UI) AnswersRevealed(event quiz.RevealEvent) {}
+
+func (ui *UI) Score(event quiz.ScoreEvent) {}
+
+// Clears the screen and writes the results made by FormatResult, after the
+// message that the game was quit when it was
+func (ui *UI) GameOver(event quiz.GameOverEvent) {
+	fmt.Fprint(ui.out, clearScreen)
+	fmt.Fprintln(ui.out, event.Text)
+}
+
+func (ui *UI) Message(text string) {
+	fmt.Fprint(ui.out, text)
+}
+
+// Reads key presses from stdin in the background, once for the whole game
+func (ui *UI) startKeys(stdin io.Reader) {
+	if ui.keys != nil {
+		return
+	}
+	ui.keys = make(chan keyPress)
+	go readKeys(bufio.NewReader(stdin), ui.keys)
+}
+
+// Turns the bytes typed into key presses until stdin is closed
+func readKeys(reader *bufio.Reader, keys chan<- keyPress) {
+	defer close(keys)
+	for {
+		char, err := reader.ReadByte()
+		if err != nil {
+			return
+		}
+
+		press := keyPress{key: keyOther}
+		switch {
+		case char == '\033':
+			// Arrow keys arrive as ESC [ A and ESC [ B
+			next, err := reader.ReadByte()
+			if err != nil {
+				return
+			}
+			if next != '[' {
+				break
+			}
+			arrow, err := reader.ReadByte()
+			if err != nil {
+				return
+			}
+			if arrow == 'A' {
+				press.key = keyUp
+			} else if arrow == 'B' {
+				press.key = keyDown
+			}
+		case char == 'k':
+			press.key = keyUp
+		case char == 'j':
+			press.key = keyDown
+		case char == '\r' || char == '\n' || char == ' ':
+			press.key = keyEnter
+		case char >= '1' && char <= '9':
+			press = keyPress{key: keyDigit, digit: int(char - '0')}
+		case char == 'q' || char == 3:
+			press.key = keyQuit
+		}
+		keys <- press
+	}
+}
+
+func (ui *UI) questionScreen(secondsLeft int) string {
+	var builder strings.Builder
+	ui.writeHeader(&builder, secondsLeft)
+
+	for index, label := range quiz.SortedLabels(ui.answerMap) {
+		line := fmt.Sprintf(" %s  %s ", label, ui.answerMap[label])
+		if index == ui.selected {
+			builder.WriteString(fmt.Sprintf(" ▶%s%s%s\n", colorReverse, line, colorReset))
+		} else {
+			builder.WriteString(fmt.Sprintf("  %s\n", line))
+		}
+	}
+	builder.WriteString(fmt.Sprintf("\n %s\n", ui.localizer.Text("tui_keys", len(ui.answerMap))))
+
+	return builder.String()
+}
+
+func (ui *UI) verdictScreen(userInput string, correct bool) string {
+	var builder strings.Builder
+	ui.writeHeader(&builder, -1)
+
+	for _, label := range quiz.SortedLabels(ui.answerMap) {
+		answer := ui.answerMap[label]
+		switch {
+		case answer == ui.question.RightAnswer:
+			builder.WriteString(fmt.Sprintf(" %s✔ %s  %s%s\n", colorGreen, label, answer, colorReset))
+		case label == userInput:
+			builder.WriteString(fmt.Sprintf(" %s✘ %s  %s%s\n", colorRed, label, answer, colorReset))
+		default:
+			builder.WriteString(fmt.Sprintf("   %s  %s\n", label, answer))
+		}
+	}
+
+	builder.WriteString("\n ")
+	if correct {
+		builder.WriteString(fmt.Sprintf("%s%s%s", colorGreen, ui.localizer.Text("tui_correct"), colorReset))
+	} else if ui.timedOut {
+		builder.WriteString(fmt.Sprintf("%s%s%s %s", colorRed, ui.localizer.Text("tui_time_up"), colorReset, ui.localizer.Text("reveal", ui.question.RightAnswer)))
+	} else {
+		builder.WriteString(fmt.Sprintf("%s%s%s %s", colorRed, ui.localizer.Text("tui_wrong"), colorReset, ui.localizer.Text("reveal", ui.question.RightAnswer)))
+	}
+	builder.WriteString("\n")
+	if ui.question.Explanation != "" {
+		builder.WriteString(fmt.Sprintf("\n %s\n", ui.question.Explanation))
+	}
+	if ui.question.Source != "" {
+		builder.WriteString(fmt.Sprintf(" %s\n", ui.localizer.Text("source", ui.question.Source)))
+	}
+	builder.WriteString(fmt.Sprintf("\n %s\n", ui.localizer.Text("tui_continue")))
+
+	return builder.String()
+}
+
+// Writes the question number, the progress bar, the countdown unless
+// secondsLeft is negative, and the question
+func (ui *UI) writeHeader(builder *strings.Builder, secondsLeft int) {
+	builder.WriteString(clearScreen)
+	builder.WriteString(fmt.Sprintf("%s Trivia%s  %s\n", colorBold, colorReset, ui.localizer.Text("tui_question", ui.number, ui.total)))
+	builder.WriteString(fmt.Sprintf(" %s", progressBar(ui.number-1, ui.total)))
+	if ui.timeLimit > 0 && secondsLeft >= 0 {
+		builder.WriteString(fmt.Sprintf("   ⏱ %ds", secondsLeft))
+	}
+	builder.WriteString("\n\n")
+
+	if ui.question.Category != "" {
+		builder.WriteString(fmt.Sprintf(" %s\n", ui.question.Category))
+	}
+	text := quiz.RenderBlocks(ui.question.Blocks(), true, ui.localizer)
+	for _, line := range strings.Split(text, "\n") {
+		builder.WriteString(fmt.Sprintf(" %s%s%s\n", colorBold, line, colorReset))
+	}
+	builder.WriteString("\n")
+}
+
+func progressBar(done int, total int) string {
+	filled := 0
+	percent := 0
+	if total > 0 {
+		filled = done * progressWidth / total
+		percent = done * 100 / total
+	}
+	return fmt.Sprintf("[%s%s] %3d%%", strings.Repeat("█", filled), strings.Repeat("░", progressWidth-filled), percent)
+}
+
+// Returns the whole seconds left until the deadline, rounded up
+func secondsLeft(deadline time.Time) int {
+	left := time.Until(deadline)
+	if left <= 0 {
+		return 0
+	}
+	return int((left + time.Second - 1) / time.Second)
+}
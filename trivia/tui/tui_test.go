@@ -0,0 +1,169 @@
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+	"trivia/quiz"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Keeps the answers in order, so the right answer is always option 4
+type noShuffle struct{}
+
+func (noShuffle) Shuffle(n int, swap func(i, j int)) {}
+
+var question = quiz.Question{
+	Question:     "Which color has the sky?",
+	RightAnswer:  "Blue",
+	WrongAnswers: [3]string{"Red", "Green", "Pink"},
+}
+
+func newTestUI(timeLimit time.Duration) (*UI, *bytes.Buffer, map[string]string) {
+	var out bytes.Buffer
+	ui := New(quiz.NewQuizWithShuffler(noShuffle{}), &out, timeLimit, nil)
+	answerMap := ui.GetAnswerMap(question)
+	ui.FormatQuestion(question, answerMap)
+	return ui, &out, answerMap
+}
+
+func readAllKeys(input string) []keyPress {
+	keys := make(chan keyPress)
+	go readKeys(bufio.NewReader(strings.NewReader(input)), keys)
+
+	var presses []keyPress
+	for press := range keys {
+		presses = append(presses, press)
+	}
+	return presses
+}
+
+func TestReadKeys(t *testing.T) {
+	presses := readAllKeys("\033[A\033[Bkj\r\n 3q\x03x")
+
+	assert.Equal(t, []keyPress{
+		{key: keyUp}, {key: keyDown}, {key: keyUp}, {key: keyDown},
+		{key: keyEnter}, {key: keyEnter}, {key: keyEnter},
+		{key: keyDigit, digit: 3}, {key: keyQuit}, {key: keyQuit}, {key: keyOther},
+	}, presses)
+}
+
+func TestGetUserInput_ArrowsAndEnter(t *testing.T) {
+	ui, out, _ := newTestUI(0)
+
+	choice, err := ui.GetUserInput(strings.NewReader("\033[B\033[B\033[A\033[A\033[A\r"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "4", choice)
+	assert.Contains(t, out.String(), clearScreen)
+	assert.Contains(t, out.String(), colorReverse+" 4  Blue "+colorReset)
+}
+
+func TestGetUserInput_Number(t *testing.T) {
+	ui, _, _ := newTestUI(0)
+
+	choice, err := ui.GetUserInput(strings.NewReader("92"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "2", choice)
+}
+
+func TestGetUserInput_Quit(t *testing.T) {
+	ui, _, _ := newTestUI(0)
+
+	_, err := ui.GetUserInput(strings.NewReader("q"))
+
+	assert.Equal(t, quiz.ErrQuit, err)
+}
+
+func TestGetUserInput_EndOfInput(t *testing.T) {
+	ui, _, _ := newTestUI(0)
+
+	_, err := ui.GetUserInput(strings.NewReader(""))
+
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestGetUserInput_TimeIsUp(t *testing.T) {
+	ui, out, _ := newTestUI(200 * time.Millisecond)
+	stdin, writer := io.Pipe()
+	defer writer.Close()
+	// The key that closes the verdict, typed after the time is up
+	go func() {
+		time.Sleep(400 * time.Millisecond)
+		writer.Write([]byte(" "))
+	}()
+
+	choice, err := ui.GetUserInput(stdin)
+
+	assert.Equal(t, quiz.ErrTimeUp, err)
+	assert.Equal(t, "", choice)
+	assert.Contains(t, out.String(), "⏱ 1s")
+	assert.Contains(t, out.String(), "Time is up.")
+	assert.Contains(t, ui.FormatResult(0, 1), "Which color has the sky? (no answer)")
+}
+
+func TestGetUserInput_QuitWhenTimeIsUp(t *testing.T) {
+	ui, _, _ := newTestUI(200 * time.Millisecond)
+	stdin, writer := io.Pipe()
+	defer writer.Close()
+	go func() {
+		time.Sleep(400 * time.Millisecond)
+		writer.Write([]byte("q"))
+	}()
+
+	_, err := ui.GetUserInput(stdin)
+	assert.Equal(t, quiz.ErrTimeUp, err)
+
+	_, err = ui.GetUserInput(stdin)
+	assert.Equal(t, quiz.ErrQuit, err)
+}
+
+func TestVerify_ShowsVerdict(t *testing.T) {
+	ui, out, answerMap := newTestUI(0)
+
+	choice, err := ui.GetUserInput(strings.NewReader("1"))
+	assert.Nil(t, err)
+	correct, err := ui.Verify(question, answerMap, choice)
+
+	assert.Nil(t, err)
+	assert.False(t, correct)
+	assert.Contains(t, out.String(), colorRed+"✘ 1  Red"+colorReset)
+	assert.Contains(t, out.String(), colorGreen+"✔ 4  Blue"+colorReset)
+	assert.Contains(t, out.String(), "The correct answer is 'Blue'")
+}
+
+func TestFormatResult(t *testing.T) {
+	ui, _, answerMap := newTestUI(0)
+	ui.GetUserInput(strings.NewReader("4"))
+	ui.Verify(question, answerMap, "4")
+
+	result := ui.FormatResult(1, 1)
+
+	assert.Contains(t, result, "You got 1 of 1 correct answer.")
+	assert.Contains(t, result, "100%")
+	assert.Contains(t, result, "✔"+colorReset+" Which color has the sky?")
+	assert.NotContains(t, result, clearScreen)
+}
+
+func TestProgressBar(t *testing.T) {
+	assert.Equal(t, "["+strings.Repeat("█", 10)+strings.Repeat("░", 20)+"]  33%", progressBar(1, 3))
+	assert.Equal(t, "["+strings.Repeat("░", 30)+"]   0%", progressBar(0, 0))
+}
+
+func TestRenderer_OnlyResults(t *testing.T) {
+	ui, out, answerMap := newTestUI(0)
+	var renderer quiz.Renderer = ui
+
+	renderer.QuestionShown(quiz.QuestionEvent{Number: 1, NumberQuestions: 1, Question: question.Question, Options: answerMap})
+	renderer.AnswerVerdict(quiz.VerdictEvent{Question: question.Question, RightAnswer: "Blue"})
+	renderer.Score(quiz.ScoreEvent{Text: "Round score"})
+	assert.Empty(t, out.String())
+
+	renderer.GameOver(quiz.GameOverEvent{Text: "Results screen", Review: []quiz.VerdictEvent{{Question: question.Question}}})
+	assert.Equal(t, clearScreen+"Results screen\n", out.String())
+}
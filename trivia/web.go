@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+	"trivia/quiz"
+	"trivia/web"
+)
+
+func webServer(args []string) error {
+	flags := flag.NewFlagSet("web", flag.ExitOnError)
+	address := flags.String("addr", ":8080", "Address to listen on")
+	players := flags.Int("players", 0, "Start when this many players have joined (0 waits for the start button)")
+	timeLimit := flags.Duration("time-limit", 20*time.Second, "Time to answer each question")
+	flags.Parse(args)
+
+	quizGame, _ := newQuiz(0)
+	configuration, err := quizGame.ReadConfigurationFromYAML(configFile)
+	if err != nil {
+		return err
+	}
+	questions, err := quizGame.GetQuestions(configuration)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", *address)
+	if err != nil {
+		return err
+	}
+	match := quiz.NewMatch(quizGame, questions, *timeLimit)
+	server := web.NewServer(match)
+	go http.Serve(listener, server.Handler())
+
+	fmt.Printf("Players join at http://%s/\n", listener.Addr())
+	fmt.Printf("Show the game on a shared screen at http://%s/?screen\n", listener.Addr())
+
+	scores := server.Run(*players)
+	fmt.Println(quiz.FormatRanking(scores, nil))
+
+	// Keep serving so that the final scores can still be looked at
+	fmt.Println("Press Ctrl-C to stop the server.")
+	select {}
+}
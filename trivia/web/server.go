@@ -0,0 +1,196 @@
+package web
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"sync"
+	"trivia/quiz"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// Game states reported by the state endpoint
+const StatusLobby string = "lobby"
+const StatusPlaying string = "playing"
+const StatusOver string = "over"
+
+type State struct {
+	Status  string             `json:"status"`
+	Players []string           `json:"players"`
+	Scores  []quiz.PlayerScore `json:"scores,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Serves the browser client, a JSON API and WebSocket events for a match.
+// Players connect to /ws?player=NAME and answer over that connection only, so
+// nobody can answer for another player. A shared screen connects to
+// /ws?watch=1 and receives the same events without taking part.
+type Server struct {
+	match     *quiz.Match
+	start     chan struct{}
+	startOnce sync.Once
+
+	mutex  sync.Mutex
+	status string
+	scores []quiz.PlayerScore
+}
+
+func NewServer(match *quiz.Match) *Server {
+	return &Server{match: match, start: make(chan struct{}), status: StatusLobby}
+}
+
+func (server *Server) Handler() http.Handler {
+	static, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	mux.HandleFunc("/ws", server.handleWebSocket)
+	mux.HandleFunc("/api/state", server.handleState)
+	mux.HandleFunc("/api/start", server.handleStart)
+
+	return mux
+}
+
+// Plays the match once the given number of players has joined or a start
+// is requested through the API, and returns the final scores
+func (server *Server) Run(players int) []quiz.PlayerScore {
+	if players > 0 {
+		server.match.WaitForPlayers(players, server.start)
+	} else {
+		<-server.start
+	}
+	server.setStatus(StatusPlaying, nil)
+
+	scores := server.match.Play()
+	server.setStatus(StatusOver, scores)
+
+	return scores
+}
+
+// Starts the match without waiting for more players
+func (server *Server) Start() {
+	server.startOnce.Do(func() { close(server.start) })
+}
+
+func (server *Server) State() State {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return State{Status: server.status, Players: server.match.Players(), Scores: server.scores}
+}
+
+func (server *Server) setStatus(status string, scores []quiz.PlayerScore) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.status = status
+	server.scores = scores
+}
+
+func (server *Server) handleState(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeJSON(writer, http.StatusMethodNotAllowed, errorResponse{Error: "Use GET"})
+		return
+	}
+	writeJSON(writer, http.StatusOK, server.State())
+}
+
+func (server *Server) handleStart(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writeJSON(writer, http.StatusMethodNotAllowed, errorResponse{Error: "Use POST"})
+		return
+	}
+	server.Start()
+	writeJSON(writer, http.StatusAccepted, server.State())
+}
+
+func (server *Server) handleWebSocket(writer http.ResponseWriter, request *http.Request) {
+	player := request.URL.Query().Get("player")
+	watch := request.URL.Query().Get("watch") != ""
+	if player == "" && !watch {
+		writeJSON(writer, http.StatusBadRequest, errorResponse{Error: "Missing player or watch parameter"})
+		return
+	}
+
+	conn, err := upgrade(writer, request)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if watch {
+		id, events := server.match.Watch()
+		defer server.match.Unwatch(id)
+		go func() {
+			// Watchers only listen; reading notices when they disconnect
+			for {
+				if _, err := conn.ReadMessage(); err != nil {
+					server.match.Unwatch(id)
+					return
+				}
+			}
+		}()
+		writeEvents(conn, events)
+		return
+	}
+
+	events, err := server.match.Join(player)
+	if err != nil {
+		message, _ := json.Marshal(quiz.Event{Type: quiz.EventError, Message: err.Error()})
+		conn.WriteMessage(message)
+		return
+	}
+	defer server.match.Leave(player)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		writeEvents(conn, events)
+		conn.Close()
+	}()
+
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			server.match.Leave(player)
+			break
+		}
+		var message quiz.Message
+		if json.Unmarshal(data, &message) != nil || message.Type != quiz.MessageAnswer {
+			server.match.SendError(player, "Expected an answer message")
+			continue
+		}
+		err = server.match.Answer(player, message.Choice)
+		if err != nil {
+			server.match.SendError(player, err.Error())
+		}
+	}
+	<-done
+}
+
+func writeEvents(conn *websocketConn, events <-chan quiz.Event) {
+	for event := range events {
+		message, err := json.Marshal(event)
+		if err != nil {
+			continue
+		}
+		if conn.WriteMessage(message) != nil {
+			return
+		}
+	}
+}
+
+func writeJSON(writer http.ResponseWriter, status int, value interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(value)
+}
@@ -0,0 +1,136 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"trivia/quiz"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Keeps the answers in order, so the right answer is always option 4
+type noShuffle struct{}
+
+func (noShuffle) Shuffle(n int, swap func(i, j int)) {}
+
+var testQuestions = []quiz.Question{
+	{Question: "Which language is this written in?", RightAnswer: "Go", WrongAnswers: [3]string{"Python", "Java", "Ruby"}},
+}
+
+func startServer(t *testing.T) (*Server, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := NewServer(quiz.NewMatch(quiz.NewQuizWithShuffler(noShuffle{}), testQuestions, 5*time.Second))
+	go http.Serve(listener, server.Handler())
+
+	return server, listener.Addr().String()
+}
+
+func (client *testClient) receiveEvent(t *testing.T) quiz.Event {
+	_, message, err := client.receive()
+	assert.NoError(t, err)
+	var event quiz.Event
+	assert.NoError(t, json.Unmarshal([]byte(message), &event))
+	return event
+}
+
+// Returns the next event of the given type
+func (client *testClient) waitFor(t *testing.T, eventType string) quiz.Event {
+	for {
+		event := client.receiveEvent(t)
+		if event.Type == eventType {
+			return event
+		}
+	}
+}
+
+func TestServesIndexPage(t *testing.T) {
+	server := NewServer(quiz.NewMatch(quiz.NewQuizWithShuffler(noShuffle{}), nil, time.Second))
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "<title>Trivia</title>")
+}
+
+func TestStateAndStartEndpoints(t *testing.T) {
+	server := NewServer(quiz.NewMatch(quiz.NewQuizWithShuffler(noShuffle{}), nil, time.Second))
+	handler := server.Handler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/api/state", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status":"lobby","players":null}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/api/start", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("POST", "/api/start", nil))
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+
+	scores := server.Run(0)
+	assert.Empty(t, scores)
+	assert.Equal(t, StatusOver, server.State().Status)
+}
+
+func TestNoAnswerEndpoint(t *testing.T) {
+	server := NewServer(quiz.NewMatch(quiz.NewQuizWithShuffler(noShuffle{}), nil, time.Second))
+	server.match.Join("anna")
+
+	// Answers are only taken over the player's own WebSocket
+	recorder := httptest.NewRecorder()
+	body := bytes.NewBufferString(`{"player":"anna","choice":"1"}`)
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest("POST", "/api/answer", body))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestWebSocketGame(t *testing.T) {
+	server, address := startServer(t)
+
+	screen := dialWebSocket(t, address, "/ws?watch=1")
+	defer screen.connection.Close()
+	assert.Equal(t, quiz.EventLobby, screen.receiveEvent(t).Type)
+
+	anna := dialWebSocket(t, address, "/ws?player=anna")
+	defer anna.connection.Close()
+	assert.Equal(t, []string{"anna"}, anna.waitFor(t, quiz.EventLobby).Players)
+
+	done := make(chan []quiz.PlayerScore)
+	go func() { done <- server.Run(1) }()
+
+	question := anna.waitFor(t, quiz.EventQuestion)
+	assert.Equal(t, "Which language is this written in?", question.Question)
+	assert.Equal(t, StatusPlaying, server.State().Status)
+
+	anna.send(opText, `{"type":"hello"}`)
+	assert.Equal(t, "Expected an answer message", anna.waitFor(t, quiz.EventError).Message)
+	anna.send(opText, `{"type":"answer","choice":"4"}`)
+
+	reveal := screen.waitFor(t, quiz.EventReveal)
+	assert.Equal(t, []quiz.PlayerResult{{Player: "anna", Answer: "Go", Correct: true}}, reveal.Results)
+	gameOver := anna.waitFor(t, quiz.EventGameOver)
+	assert.Equal(t, 1, gameOver.Scores[0].CorrectAnswers)
+
+	scores := <-done
+	assert.Equal(t, scores, server.State().Scores)
+}
+
+func TestWebSocketRequiresPlayer(t *testing.T) {
+	_, address := startServer(t)
+
+	response, err := http.Get("http://" + address + "/ws")
+	assert.NoError(t, err)
+	body, _ := ioutil.ReadAll(response.Body)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.Contains(t, string(body), "Missing player")
+}
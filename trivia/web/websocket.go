@@ -0,0 +1,202 @@
+package web
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Minimal server side of the WebSocket protocol (RFC 6455), enough for the
+// browser client: text messages, ping/pong and close.
+
+const websocketGUID string = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const maxMessageSize int = 64 * 1024
+
+const (
+	opText  byte = 0x1
+	opClose byte = 0x8
+	opPing  byte = 0x9
+	opPong  byte = 0xA
+)
+
+type websocketConn struct {
+	connection net.Conn
+	reader     *bufio.Reader
+	writeMutex sync.Mutex
+}
+
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func headerContains(header http.Header, name string, value string) bool {
+	for _, field := range header[http.CanonicalHeaderKey(name)] {
+		for _, part := range strings.Split(field, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Completes the WebSocket handshake and takes over the connection
+func upgrade(writer http.ResponseWriter, request *http.Request) (*websocketConn, error) {
+	key := request.Header.Get("Sec-WebSocket-Key")
+	if request.Method != http.MethodGet ||
+		!headerContains(request.Header, "Connection", "upgrade") ||
+		!headerContains(request.Header, "Upgrade", "websocket") ||
+		key == "" {
+		http.Error(writer, "Expected a WebSocket upgrade", http.StatusBadRequest)
+		return nil, fmt.Errorf("Not a WebSocket handshake")
+	}
+
+	hijacker, ok := writer.(http.Hijacker)
+	if !ok {
+		http.Error(writer, "WebSocket not supported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("Connection cannot be hijacked")
+	}
+	connection, buffered, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + websocketAccept(key) + "\r\n\r\n"
+	_, err = buffered.WriteString(response)
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		connection.Close()
+		return nil, err
+	}
+
+	return &websocketConn{connection: connection, reader: buffered.Reader}, nil
+}
+
+// Reads the next text or binary message, answering pings on the way.
+// Returns io.EOF when the client closes the connection.
+func (conn *websocketConn) ReadMessage() ([]byte, error) {
+	var message []byte
+	for {
+		final, opcode, payload, err := conn.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case opClose:
+			conn.writeFrame(opClose, nil)
+			return nil, io.EOF
+		case opPing:
+			err = conn.writeFrame(opPong, payload)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		}
+
+		message = append(message, payload...)
+		if len(message) > maxMessageSize {
+			return nil, fmt.Errorf("Message too large")
+		}
+		if final {
+			return message, nil
+		}
+	}
+}
+
+func (conn *websocketConn) readFrame() (bool, byte, []byte, error) {
+	var header [2]byte
+	_, err := io.ReadFull(conn.reader, header[:])
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	final := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var extended [2]byte
+		_, err = io.ReadFull(conn.reader, extended[:])
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		_, err = io.ReadFull(conn.reader, extended[:])
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if err != nil {
+		return false, 0, nil, err
+	}
+	if length > uint64(maxMessageSize) {
+		return false, 0, nil, fmt.Errorf("Frame too large")
+	}
+	// Clients must mask every frame they send
+	if !masked {
+		return false, 0, nil, fmt.Errorf("Unmasked client frame")
+	}
+
+	var mask [4]byte
+	_, err = io.ReadFull(conn.reader, mask[:])
+	if err != nil {
+		return false, 0, nil, err
+	}
+	payload := make([]byte, length)
+	_, err = io.ReadFull(conn.reader, payload)
+	if err != nil {
+		return false, 0, nil, err
+	}
+	for index := range payload {
+		payload[index] ^= mask[index%4]
+	}
+
+	return final, opcode, payload, nil
+}
+
+func (conn *websocketConn) WriteMessage(message []byte) error {
+	return conn.writeFrame(opText, message)
+}
+
+func (conn *websocketConn) writeFrame(opcode byte, payload []byte) error {
+	conn.writeMutex.Lock()
+	defer conn.writeMutex.Unlock()
+
+	frame := []byte{0x80 | opcode}
+	length := len(payload)
+	switch {
+	case length < 126:
+		frame = append(frame, byte(length))
+	case length <= 0xFFFF:
+		frame = append(frame, 126, byte(length>>8), byte(length))
+	default:
+		var extended [8]byte
+		binary.BigEndian.PutUint64(extended[:], uint64(length))
+		frame = append(append(frame, 127), extended[:]...)
+	}
+	frame = append(frame, payload...)
+
+	_, err := conn.connection.Write(frame)
+	return err
+}
+
+func (conn *websocketConn) Close() error {
+	conn.writeFrame(opClose, nil)
+	return conn.connection.Close()
+}
@@ -0,0 +1,150 @@
+package web
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test client side of a WebSocket connection
+type testClient struct {
+	connection net.Conn
+	reader     *bufio.Reader
+}
+
+func dialWebSocket(t *testing.T, address string, path string) *testClient {
+	connection, err := net.Dial("tcp", address)
+	assert.NoError(t, err)
+
+	fmt.Fprintf(connection, "GET %s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: keep-alive, Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n", path, address)
+
+	reader := bufio.NewReader(connection)
+	response, err := http.ReadResponse(reader, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", response.Header.Get("Sec-WebSocket-Accept"))
+
+	return &testClient{connection: connection, reader: reader}
+}
+
+func (client *testClient) send(opcode byte, payload string) {
+	mask := []byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opcode}
+	if len(payload) < 126 {
+		frame = append(frame, 0x80|byte(len(payload)))
+	} else {
+		frame = append(frame, 0x80|126, byte(len(payload)>>8), byte(len(payload)))
+	}
+	frame = append(frame, mask...)
+	for index := range payload {
+		frame = append(frame, payload[index]^mask[index%4])
+	}
+	client.connection.Write(frame)
+}
+
+func (client *testClient) receive() (byte, string, error) {
+	var header [2]byte
+	_, err := io.ReadFull(client.reader, header[:])
+	if err != nil {
+		return 0, "", err
+	}
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var extended [2]byte
+		io.ReadFull(client.reader, extended[:])
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	}
+	payload := make([]byte, length)
+	_, err = io.ReadFull(client.reader, payload)
+	return header[0] & 0x0F, string(payload), err
+}
+
+func echoServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go http.Serve(listener, http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		conn, err := upgrade(writer, request)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(message)
+		}
+	}))
+
+	return listener.Addr().String()
+}
+
+func TestWebsocketAccept(t *testing.T) {
+	// Example from RFC 6455
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", websocketAccept("dGhlIHNhbXBsZSBub25jZQ=="))
+}
+
+func TestWebsocketEcho(t *testing.T) {
+	client := dialWebSocket(t, echoServer(t), "/")
+	defer client.connection.Close()
+
+	client.send(opText, "hello")
+	opcode, message, err := client.receive()
+	assert.NoError(t, err)
+	assert.Equal(t, opText, opcode)
+	assert.Equal(t, "hello", message)
+
+	long := strings.Repeat("x", 300)
+	client.send(opText, long)
+	_, message, _ = client.receive()
+	assert.Equal(t, long, message)
+
+	client.send(opPing, "ping")
+	opcode, message, _ = client.receive()
+	assert.Equal(t, opPong, opcode)
+	assert.Equal(t, "ping", message)
+
+	client.send(opClose, "")
+	opcode, _, _ = client.receive()
+	assert.Equal(t, opClose, opcode)
+}
+
+func TestWebsocketFragmentedMessage(t *testing.T) {
+	client := dialWebSocket(t, echoServer(t), "/")
+	defer client.connection.Close()
+
+	// Text frame without FIN followed by a final continuation frame
+	mask := []byte{0, 0, 0, 0}
+	client.connection.Write(append([]byte{opText, 0x80 | 3}, append(mask, "hel"...)...))
+	client.connection.Write(append([]byte{0x80, 0x80 | 2}, append(mask, "lo"...)...))
+
+	_, message, err := client.receive()
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", message)
+}
+
+func TestWebsocketRejectsUnmaskedFrames(t *testing.T) {
+	client := dialWebSocket(t, echoServer(t), "/")
+	defer client.connection.Close()
+
+	client.connection.Write([]byte{0x80 | opText, 2, 'h', 'i'})
+	opcode, _, _ := client.receive()
+	assert.Equal(t, opClose, opcode)
+}
+
+func TestUpgradeRequiresHandshake(t *testing.T) {
+	response, err := http.Get("http://" + echoServer(t) + "/")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}